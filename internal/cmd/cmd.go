@@ -16,9 +16,10 @@ package cmd
 
 import (
 	"bufio"
-	"errors"
 	"io"
 	"os"
+	"sort"
+	"strings"
 
 	"encoding/json"
 	"io/ioutil"
@@ -33,14 +34,32 @@ import (
 func Execute(version string, stdin io.Reader, stdout, stderr io.Writer, args []string) error {
 	// options
 	const (
-		versionFlag = "version"
-		inputFlag   = "input"
-		outputFlag  = "output"
-		reverseFlag = "reverse"
-		prefixFlag  = "prefix"
-		suffixFlag  = "suffix"
-		levelFlag   = "level"
-		strictFlag  = "strict"
+		versionFlag      = "version"
+		inputFlag        = "input"
+		outputFlag       = "output"
+		reverseFlag      = "reverse"
+		prefixFlag       = "prefix"
+		suffixFlag       = "suffix"
+		levelFlag        = "level"
+		strictFlag       = "strict"
+		schemeFlag       = "scheme"
+		calverFormatFlag = "calver-format"
+		satisfiesFlag    = "satisfies"
+		missingFlag      = "missing-segments"
+		stableFlag       = "stable"
+		tieBreakFlag     = "tie-break"
+		suffixKeyFlag    = "suffix-key"
+		groupByFlag      = "group-by"
+		bufferSizeFlag   = "buffer-size"
+		parallelFlag     = "parallel"
+		batchSizeFlag    = "batch-size"
+		separatorFlag    = "field-separator"
+		keyFlag          = "key"
+		extractFlag      = "extract"
+		extractPatFlag   = "extract-pattern"
+		onlyVersionFlag  = "only-version"
+		invalidFlag      = "invalid"
+		reportFlag       = "report-invalid"
 	)
 
 	// values of --input
@@ -55,6 +74,37 @@ func Execute(version string, stdin io.Reader, stdout, stderr io.Writer, args []s
 		jsonOutput  = "json"
 	)
 
+	// values of --missing-segments
+	const (
+		zeroMissing  = "zero"
+		lessMissing  = "less"
+		errorMissing = "error"
+	)
+
+	// values of --tie-break
+	const (
+		inputTieBreak   = "input"
+		lexicalTieBreak = "lexical"
+		suffixTieBreak  = "suffix"
+	)
+
+	// values of --invalid
+	const (
+		dropInvalid   = "drop"
+		firstInvalid  = "first"
+		lastInvalid   = "last"
+		errorInvalid  = "error"
+		stderrInvalid = "stderr"
+		// suffix of "first" and "last" to sort invalid versions lexically
+		lexicalInvalid = ":lexical"
+	)
+
+	// values of --report-invalid
+	const (
+		textReport = "text"
+		jsonReport = "json"
+	)
+
 	cmd := &cobra.Command{
 		Use:          "vsort [flags] [files]",
 		SilenceUsage: true,
@@ -73,7 +123,7 @@ func Execute(version string, stdin io.Reader, stdout, stderr io.Writer, args []s
 				return err
 			}
 
-			inputFunc, ok := map[string]func(io.Reader) ([]string, error){
+			inputFunc, ok := map[string]func(io.Reader, string, func(entry) error) error{
 				linesInput: readLines, jsonInput: readJSON,
 			}[input]
 			if !ok {
@@ -86,8 +136,8 @@ func Execute(version string, stdin io.Reader, stdout, stderr io.Writer, args []s
 				return err
 			}
 
-			outputFunc, ok := map[string]func(*cobra.Command, []string) error{
-				linesOutput: outputLines, jsonOutput: outputJSON,
+			outputFunc, ok := map[string]func(*cobra.Command) versionWriter{
+				linesOutput: newLinesWriter, jsonOutput: newJSONWriter,
 			}[output]
 			if !ok {
 				return fmt.Errorf("unknown output format: %q (expected %q or %q)", output, linesOutput, jsonOutput)
@@ -105,12 +155,24 @@ func Execute(version string, stdin io.Reader, stdout, stderr io.Writer, args []s
 				return err
 			}
 
+			// Get --group-by
+			groupBy, err := cmd.Flags().GetString(groupByFlag)
+			if err != nil {
+				return err
+			}
+
 			// Get --suffix
 			suffix, err := cmd.Flags().GetString(suffixFlag)
 			if err != nil {
 				return err
 			}
 
+			// Get --suffix-key
+			suffixKeys, err := cmd.Flags().GetStringSlice(suffixKeyFlag)
+			if err != nil {
+				return err
+			}
+
 			// Get --level
 			level, err := cmd.Flags().GetInt(levelFlag)
 			if err != nil {
@@ -123,6 +185,173 @@ func Execute(version string, stdin io.Reader, stdout, stderr io.Writer, args []s
 				return err
 			}
 
+			// Get --invalid
+			invalidName, err := cmd.Flags().GetString(invalidFlag)
+			if err != nil {
+				return err
+			}
+
+			invalid, sortInvalid := invalidName, false
+			if strings.HasSuffix(invalid, lexicalInvalid) {
+				invalid, sortInvalid = strings.TrimSuffix(invalid, lexicalInvalid), true
+			}
+			switch invalid {
+			case firstInvalid, lastInvalid:
+			case dropInvalid, errorInvalid, stderrInvalid:
+				if !sortInvalid {
+					break
+				}
+				fallthrough
+			default:
+				return fmt.Errorf("unknown invalid version policy: %q (expected %q, %q, %q, %q or %q)", invalidName, dropInvalid, firstInvalid, lastInvalid, errorInvalid, stderrInvalid)
+			}
+
+			// --strict is the same as --invalid=error
+			if strict {
+				if cmd.Flags().Changed(invalidFlag) && invalid != errorInvalid {
+					return fmt.Errorf("--%s conflicts with --%s=%s", strictFlag, invalidFlag, invalidName)
+				}
+				invalid = errorInvalid
+			}
+
+			// Get --report-invalid
+			reportFormat, err := cmd.Flags().GetString(reportFlag)
+			if err != nil {
+				return err
+			}
+
+			switch reportFormat {
+			case "":
+				// invalid versions are reported in text with --invalid=stderr
+				if invalid == stderrInvalid {
+					reportFormat = textReport
+				}
+			case textReport, jsonReport:
+			default:
+				return fmt.Errorf("unknown report format: %q (expected %q or %q)", reportFormat, textReport, jsonReport)
+			}
+
+			// Get --scheme
+			scheme, err := cmd.Flags().GetString(schemeFlag)
+			if err != nil {
+				return err
+			}
+
+			// Get --calver-format
+			calverFormat, err := cmd.Flags().GetString(calverFormatFlag)
+			if err != nil {
+				return err
+			}
+
+			// Get --satisfies
+			satisfies, err := cmd.Flags().GetString(satisfiesFlag)
+			if err != nil {
+				return err
+			}
+
+			// Get --missing-segments
+			missing, err := cmd.Flags().GetString(missingFlag)
+			if err != nil {
+				return err
+			}
+
+			padding, ok := map[string]vsort.WithPadding{
+				zeroMissing: vsort.WithPadding(vsort.PadZero), lessMissing: vsort.WithPadding(vsort.PadLess), errorMissing: vsort.WithPadding(vsort.PadError),
+			}[missing]
+			if !ok {
+				return fmt.Errorf("unknown missing segments policy: %q (expected %q, %q or %q)", missing, zeroMissing, lessMissing, errorMissing)
+			}
+
+			// Get --stable
+			stable, err := cmd.Flags().GetBool(stableFlag)
+			if err != nil {
+				return err
+			}
+
+			// Get --tie-break
+			tieBreakName, err := cmd.Flags().GetString(tieBreakFlag)
+			if err != nil {
+				return err
+			}
+
+			tieBreak := vsort.WithTieBreak(vsort.NoTieBreak)
+			if tieBreakName != "" {
+				tieBreak, ok = map[string]vsort.WithTieBreak{
+					inputTieBreak: vsort.WithTieBreak(vsort.TieBreakInput), lexicalTieBreak: vsort.WithTieBreak(vsort.TieBreakLexical), suffixTieBreak: vsort.WithTieBreak(vsort.TieBreakSuffix),
+				}[tieBreakName]
+				if !ok {
+					return fmt.Errorf("unknown tie-break: %q (expected %q, %q or %q)", tieBreakName, inputTieBreak, lexicalTieBreak, suffixTieBreak)
+				}
+			}
+
+			// Get --buffer-size
+			bufferSizeText, err := cmd.Flags().GetString(bufferSizeFlag)
+			if err != nil {
+				return err
+			}
+
+			bufferSize, err := parseBufferSize(bufferSizeText)
+			if err != nil {
+				return err
+			}
+
+			// Get --parallel
+			parallel, err := cmd.Flags().GetInt(parallelFlag)
+			if err != nil {
+				return err
+			}
+			if parallel < 1 {
+				return fmt.Errorf("parallel should be positive: %d", parallel)
+			}
+
+			// Get --batch-size
+			batchSize, err := cmd.Flags().GetInt(batchSizeFlag)
+			if err != nil {
+				return err
+			}
+			if batchSize < 2 {
+				return fmt.Errorf("batch size should be at least 2: %d", batchSize)
+			}
+
+			// Get --field-separator
+			separator, err := cmd.Flags().GetString(separatorFlag)
+			if err != nil {
+				return err
+			}
+
+			// Get --key
+			keyTexts, err := cmd.Flags().GetStringArray(keyFlag)
+			if err != nil {
+				return err
+			}
+
+			keys := make([]fieldKey, len(keyTexts))
+			for i, k := range keyTexts {
+				if keys[i], err = parseFieldKey(k); err != nil {
+					return err
+				}
+			}
+
+			// Get --extract
+			extract, err := cmd.Flags().GetBool(extractFlag)
+			if err != nil {
+				return err
+			}
+
+			// Get --extract-pattern
+			extractPattern, err := cmd.Flags().GetString(extractPatFlag)
+			if err != nil {
+				return err
+			}
+			// --extract-pattern implies --extract
+			extract = extract || extractPattern != ""
+
+			// Get --only-version
+			onlyVersion, err := cmd.Flags().GetBool(onlyVersionFlag)
+			if err != nil {
+				return err
+			}
+
 			type inputStream struct {
 				name string
 				r    io.Reader
@@ -145,48 +374,140 @@ func Execute(version string, stdin io.Reader, stdout, stderr io.Writer, args []s
 				}
 			}
 
-			var versions []string
-			for _, i := range is {
-				vs, err := inputFunc(i.r)
-				if err != nil {
-					return fmt.Errorf("cannot read from %s: %w", i.name, err)
-				}
-				versions = append(versions, vs...)
-			}
-
 			order := vsort.WithOrder(vsort.Asc)
 			if reverse {
 				order = vsort.WithOrder(vsort.Desc)
 			}
 
-			options := []vsort.Option{order, vsort.WithPrefix(prefix), vsort.WithLevel(level)}
+			options := []vsort.Option{order, vsort.WithPrefix(prefix), vsort.WithLevel(level), vsort.WithScheme(scheme), vsort.WithStable(stable), tieBreak}
+			if groupBy != "" {
+				options = append(options, vsort.WithGroupBy(groupBy))
+			}
 			if suffix != "" {
 				options = append(options, vsort.WithSuffix(suffix))
 			}
+			for _, k := range suffixKeys {
+				options = append(options, vsort.WithSuffixKey(k))
+			}
+			if calverFormat != "" {
+				options = append(options, vsort.WithCalVerFormat(calverFormat))
+			}
+			if padding != vsort.WithPadding(vsort.PadZero) {
+				options = append(options, padding)
+			}
+			if extractPattern != "" {
+				options = append(options, vsort.WithExtractPattern(extractPattern))
+			}
 			s, err := vsort.NewSorter(options...)
 			if err != nil {
 				return err
 			}
 
-			// validate inputs
-			validated := make([]string, 0, len(versions))
-			for _, v := range versions {
-				if s.IsValid(v) {
-					validated = append(validated, v)
-				} else if strict {
-					msg := fmt.Sprintf("invalid version is contained: %s\n", v)
-					cmd.PrintErrln(msg)
-					return errors.New(msg)
+			var constraint *vsort.Constraint
+			if satisfies != "" {
+				constraint, err = s.ParseConstraint(satisfies)
+				if err != nil {
+					return err
+				}
+			}
+
+			ls, err := newLineSorter(s, reverse, separator, keys, extract)
+			if err != nil {
+				return err
+			}
+
+			// with --only-version, versions are sorted instead of lines
+			sortLs := ls
+			if onlyVersion {
+				sortLs = &lineSorter{sorter: s, reverse: reverse}
+			}
+
+			ms := newMergeSorter(sortLs, bufferSize, parallel, batchSize)
+			defer ms.Close()
+
+			// validate inputs, filter them by constraint and pass them to sorter
+			var (
+				// the first valid version and its location
+				first    entry
+				invalids []string
+			)
+			add := func(e entry) error {
+				v := e.text
+				parsed, err := ls.parse(v)
+				if err != nil {
+					d := newInvalidVersion(e, err)
+					// the error itself is printed in text with --invalid=error
+					if reportFormat == jsonReport || (reportFormat == textReport && invalid != errorInvalid) {
+						if err := reportInvalid(cmd.ErrOrStderr(), reportFormat, d); err != nil {
+							return err
+						}
+					}
+
+					switch invalid {
+					case errorInvalid:
+						return d
+					case firstInvalid, lastInvalid:
+						invalids = append(invalids, v)
+					}
+					return nil
+				}
+
+				if constraint != nil && !constraint.Check(parsed.version()) {
+					return nil
+				}
+
+				// with --missing-segments=error, versions should have the same segment count as the first one
+				if padding == vsort.WithPadding(vsort.PadError) {
+					if first.text == "" {
+						first = e
+						first.text = parsed.version().String()
+					} else if _, err := s.Compare(first.text, parsed.version().String()); err != nil {
+						return newInvalidVersion(e, fmt.Errorf("segment count is different from %q at %s", first.text, first.location()))
+					}
+				}
+
+				if onlyVersion {
+					// only the first version key is compared, and is output instead of the line
+					v := parsed.version()
+					return ms.Add(parsedLine{text: v.String(), versions: []vsort.Version{v}})
+				}
+				return ms.Add(parsed)
+			}
+
+			for _, i := range is {
+				if err := inputFunc(i.r, i.name, add); err != nil {
+					return err
 				}
 			}
 
-			s.Sort(validated)
+			if sortInvalid {
+				sort.Strings(invalids)
+			}
+			writeInvalids := func(w versionWriter) error {
+				for _, v := range invalids {
+					if err := w.Write(v); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
 
-			if err := outputFunc(cmd, validated); err != nil {
+			w := outputFunc(cmd)
+			if invalid == firstInvalid {
+				if err := writeInvalids(w); err != nil {
+					return err
+				}
+			}
+			if err := ms.Finish(w.Write); err != nil {
 				return err
 			}
+			if invalid == lastInvalid {
+				if err := writeInvalids(w); err != nil {
+					return err
+				}
+			}
 
-			return nil
+			return w.Close()
 		},
 	}
 
@@ -195,9 +516,27 @@ func Execute(version string, stdin io.Reader, stdout, stderr io.Writer, args []s
 	cmd.Flags().StringP(outputFlag, "o", linesOutput, `Specify output format. Accepted values are "lines" or "json" (default: "lines").`)
 	cmd.Flags().BoolP(reverseFlag, "r", false, "Sort in reverse order.")
 	cmd.Flags().StringP(prefixFlag, "p", "", "Expected prefix pattern of version string.")
+	cmd.Flags().String(groupByFlag, "", `Named capture group of prefix pattern to group versions by. Groups are output in lexical order (e.g. "component" for "(?P<component>[a-z-]+)/v").`)
 	cmd.Flags().StringP(suffixFlag, "s", "", "Expected suffix pattern of version string.")
+	cmd.Flags().StringSlice(suffixKeyFlag, nil, `Named capture group of suffix pattern compared as secondary key, formatted as "NAME", "NAME:numeric" or "NAME:lexical" (e.g. "build" for "-(?P<build>\d+)").`)
 	cmd.Flags().IntP(levelFlag, "L", -1, "Expected version level")
-	cmd.Flags().Bool(strictFlag, false, "Make error when invalid version is contained.")
+	cmd.Flags().Bool(strictFlag, false, "Make error when invalid version is contained. It is the same as --invalid=error.")
+	cmd.Flags().String(reportFlag, "", `Report invalid versions to stderr with locations. Accepted values are "text" ('FILE:LINE: "VERSION": REASON') or "json" (an object per line).`)
+	cmd.Flags().String(invalidFlag, dropInvalid, `Specify how invalid versions are handled. Accepted values are "drop" (omit them), "first" or "last" (output them before or after valid ones in input order, or lexically sorted with ":lexical" suffix), "error" (make error) or "stderr" (report them to stderr with locations) (default: "drop").`)
+	cmd.Flags().String(schemeFlag, vsort.Numeric, fmt.Sprintf(`Specify version scheme. Accepted values are %s (default: "numeric").`, quoteList(vsort.Schemes())))
+	cmd.Flags().String(satisfiesFlag, "", `Output only versions satisfying the constraint (e.g. ">=1.2.0, <2.0.0", "~1.4", "^0.3.1" or "1.2.x").`)
+	cmd.Flags().String(missingFlag, zeroMissing, `Specify how versions with different segment counts are compared. Accepted values are "zero" (missing segments are zero), "less" (shorter is smaller) or "error" (default: "zero").`)
+	cmd.Flags().Bool(stableFlag, false, "Keep input order of equivalent versions.")
+	cmd.Flags().String(tieBreakFlag, "", `Specify how equivalent versions are ordered. Accepted values are "input" (input order), "lexical" (whole strings) or "suffix" (stripped suffix and then prefix).`)
+	cmd.Flags().StringP(separatorFlag, "t", "", "Use SEP as field separator of --key instead of runs of white spaces.")
+	cmd.Flags().StringArrayP(keyFlag, "k", nil, `Sort lines by fields instead of whole lines. KEY is formatted as "FIELD[,FIELD2][:version|:lexical]" (e.g. "2" or "1:lexical"), and compared as version by default. Unlike sort(1), FIELD alone means only that field instead of the rest of line; use "FIELD,FIELD2" for multiple fields. It can be given multiple times.`)
+	cmd.Flags().Bool(extractFlag, false, "Sort lines by versions found in them (or in --key) with the scheme.")
+	cmd.Flags().String(extractPatFlag, "", `Find versions by the named capture group "version" of the pattern instead of the scheme (e.g. "myapp-(?P<version>[0-9.]+)"). It implies --extract.`)
+	cmd.Flags().Bool(onlyVersionFlag, false, "Output only versions of lines instead of whole lines.")
+	cmd.Flags().StringP(bufferSizeFlag, "S", "", `Use SIZE for sorting in memory, and spill sorted runs to temporary files beyond it. SIZE may be followed by a suffix "b", "K", "M", "G" or "T" (default: KiB). Unlimited if not given.`)
+	cmd.Flags().Int(parallelFlag, 1, "Sort runs of versions in parallel with N goroutines.")
+	cmd.Flags().Int(batchSizeFlag, defaultBatchSize, "Merge at most N runs at once, and merge them in multiple passes if there are more. It bounds the number of open temporary files.")
+	cmd.Flags().String(calverFormatFlag, "", `Format template of version for "calver" scheme (e.g. "YYYY.0M.MICRO").`)
 
 	cmd.SetIn(stdin)
 	cmd.SetOut(stdout)
@@ -206,46 +545,109 @@ func Execute(version string, stdin io.Reader, stdout, stderr io.Writer, args []s
 	return cmd.Execute()
 }
 
-func readLines(r io.Reader) ([]string, error) {
-	lines := make([]string, 0)
+// readLines passes each line of r to emit. An error returned by emit is returned as is.
+func readLines(r io.Reader, name string, emit func(entry) error) error {
 	scanner := bufio.NewScanner(r)
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+	for line := 1; scanner.Scan(); line++ {
+		if err := emit(entry{text: scanner.Text(), file: name, line: line}); err != nil {
+			return err
+		}
 	}
 	if err := scanner.Err(); err != nil {
-		return nil, err
+		return fmt.Errorf("cannot read from %s: %w", name, err)
 	}
 
-	return lines, nil
+	return nil
 }
 
-func readJSON(r io.Reader) ([]string, error) {
+// readJSON passes each element of a JSON array of strings in r to emit. An error returned by emit is returned as is.
+func readJSON(r io.Reader, name string, emit func(entry) error) error {
 	j, err := ioutil.ReadAll(r)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("cannot read from %s: %w", name, err)
 	}
 
 	var a []string
 	if err := json.Unmarshal(j, &a); err != nil {
-		return nil, err
+		return fmt.Errorf("cannot read from %s: %w", name, err)
+	}
+	for i, v := range a {
+		if err := emit(entry{text: v, file: name, index: i}); err != nil {
+			return err
+		}
 	}
-	return a, nil
+
+	return nil
 }
 
-func outputLines(cmd *cobra.Command, versions []string) error {
-	for _, v := range versions {
-		cmd.Println(v)
-	}
+// versionWriter outputs sorted versions one by one
+type versionWriter interface {
+	Write(v string) error
+	Close() error
+}
+
+type linesWriter struct {
+	cmd *cobra.Command
+}
+
+func newLinesWriter(cmd *cobra.Command) versionWriter {
+	return &linesWriter{cmd: cmd}
+}
+
+func (w *linesWriter) Write(v string) error {
+	w.cmd.Println(v)
+
+	return nil
+}
 
+func (w *linesWriter) Close() error {
 	return nil
 }
 
-func outputJSON(cmd *cobra.Command, versions []string) error {
-	b, err := json.Marshal(versions)
+// jsonWriter outputs versions as a JSON array
+type jsonWriter struct {
+	cmd   *cobra.Command
+	count int
+}
+
+func newJSONWriter(cmd *cobra.Command) versionWriter {
+	return &jsonWriter{cmd: cmd}
+}
+
+func (w *jsonWriter) Write(v string) error {
+	b, err := json.Marshal(v)
 	if err != nil {
 		return err
 	}
-	cmd.Print(string(b))
+
+	sep := ","
+	if w.count == 0 {
+		sep = "["
+	}
+	w.cmd.Print(sep + string(b))
+	w.count++
+
+	return nil
+}
+
+func (w *jsonWriter) Close() error {
+	if w.count == 0 {
+		w.cmd.Print("[")
+	}
+	w.cmd.Print("]")
 
 	return nil
 }
+
+// quoteList returns quoted items joined like `"a", "b" or "c"`
+func quoteList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("%q", item)
+	}
+	if len(quoted) <= 1 {
+		return strings.Join(quoted, "")
+	}
+
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
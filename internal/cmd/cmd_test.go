@@ -20,8 +20,12 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/autopp/vsort/pkg/vsort"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -111,6 +115,411 @@ func TestExecute(t *testing.T) {
 				success:  true,
 				expected: "0.0.1\n0.0.2\n0.2.0\n0.10.0\n",
 			},
+			{
+				filename: "semver",
+				contents: "1.0.0\n1.0.0-rc.1\n1.0.0-alpha\n0.9.0+build.1\n1.0.0-beta.11\n1.0.0-beta.2\n",
+				args:     []string{"--scheme", "semver"},
+				success:  true,
+				expected: "0.9.0+build.1\n1.0.0-alpha\n1.0.0-beta.2\n1.0.0-beta.11\n1.0.0-rc.1\n1.0.0\n",
+			},
+			{
+				filename: "debian",
+				contents: "2.30-1ubuntu3\n1:2.29-1\n2.30~rc1-1\n2.30+dfsg-2\n2.30-1\n",
+				args:     []string{"--scheme", "debian"},
+				success:  true,
+				expected: "2.30~rc1-1\n2.30-1\n2.30-1ubuntu3\n2.30+dfsg-2\n1:2.29-1\n",
+			},
+			{
+				filename: "rpm",
+				contents: "1.0.2k-21.el7_9\n1.0.2k-19.el7\n1:1.0.1-1\n1.0.2~beta1-1\n",
+				args:     []string{"--scheme", "rpm"},
+				success:  true,
+				expected: "1.0.2~beta1-1\n1.0.2k-19.el7\n1.0.2k-21.el7_9\n1:1.0.1-1\n",
+			},
+			{
+				filename: "pep440",
+				contents: "1.0\n1.0.post1\n1.0rc1\n1.0.dev3\n1.0alpha1\n",
+				args:     []string{"--scheme", "pep440"},
+				success:  true,
+				expected: "1.0.dev3\n1.0alpha1\n1.0rc1\n1.0\n1.0.post1\n",
+			},
+			{
+				filename: "pep440-strict",
+				contents: "1.0\n1.0.x\n",
+				args:     []string{"--scheme", "pep440", "--strict"},
+				success:  false,
+			},
+			{
+				filename: "maven",
+				contents: "1.0\n1.0-SNAPSHOT\n1.0-rc1\n1.0-sp1\n1.0-alpha-1\n",
+				args:     []string{"--scheme", "maven"},
+				success:  true,
+				expected: "1.0-alpha-1\n1.0-rc1\n1.0-SNAPSHOT\n1.0\n1.0-sp1\n",
+			},
+			{
+				filename: "rubygems",
+				contents: "1.0.0\n1.0.0.pre2\n0.9.10\n1.0.0.rc1\n0.9.9\n",
+				args:     []string{"--scheme", "rubygems"},
+				success:  true,
+				expected: "0.9.9\n0.9.10\n1.0.0.pre2\n1.0.0.rc1\n1.0.0\n",
+			},
+			{
+				filename: "calver",
+				contents: "22.04\n20.10\n22.4\n21.13\n21.10\n",
+				args:     []string{"--scheme", "calver", "--calver-format", "YY.0M"},
+				success:  true,
+				expected: "20.10\n21.10\n22.04\n",
+			},
+			{
+				filename: "calver-wo-format",
+				contents: "22.04\n20.10\n",
+				args:     []string{"--scheme", "calver"},
+				success:  false,
+			},
+			{
+				filename: "gomod",
+				contents: "v1.2.4\nv2.0.0+incompatible\nv1.2.4-0.20191109021931-daa7c04131f5\nv1.2.3\nv0.0.0-20191109021931-daa7c04131f5\n",
+				args:     []string{"--scheme", "gomod"},
+				success:  true,
+				expected: "v0.0.0-20191109021931-daa7c04131f5\nv1.2.3\nv1.2.4-0.20191109021931-daa7c04131f5\nv1.2.4\nv2.0.0+incompatible\n",
+			},
+			{
+				filename: "natural",
+				contents: "foo-1.10.tar.gz\nbar\nfoo-1.2.tar.gz\nfoo-1.2~rc1.tar.gz\n",
+				args:     []string{"--scheme", "natural"},
+				success:  true,
+				expected: "bar\nfoo-1.2~rc1.tar.gz\nfoo-1.2.tar.gz\nfoo-1.10.tar.gz\n",
+			},
+			{
+				filename: "unknown-scheme",
+				contents: "0.2.0\n0.0.1\n",
+				args:     []string{"--scheme", "unknown"},
+				success:  false,
+			},
+			{
+				filename: "satisfies",
+				contents: "1.1.0\n2.0.0\n1.10.0\n1.2.0\n1.3.0\n",
+				args:     []string{"--satisfies", ">=1.2 <2, !=1.3.0"},
+				success:  true,
+				expected: "1.2.0\n1.10.0\n",
+			},
+			{
+				filename: "satisfies-with-prefix",
+				contents: "v1.1.0\nv2.0.0\nv1.10.0\nv1.2.0-rc.1\nv1.2.0\n",
+				args:     []string{"--scheme", "semver", "--prefix", "v", "--satisfies", "^1.2"},
+				success:  true,
+				expected: "v1.2.0\nv1.10.0\n",
+			},
+			{
+				filename: "invalid-satisfies",
+				contents: "1.1.0\n",
+				args:     []string{"--satisfies", ">=1.x.2"},
+				success:  false,
+			},
+			{
+				filename: "missing-segments-zero",
+				contents: "1.2.1\n1.10\n1.2\n1.1.9\n",
+				args:     []string{"--missing-segments", "zero"},
+				success:  true,
+				expected: "1.1.9\n1.2\n1.2.1\n1.10\n",
+			},
+			{
+				filename: "missing-segments-less",
+				contents: "1.2.1\n1.2.0\n1.10\n1.2\n",
+				args:     []string{"--missing-segments", "less"},
+				success:  true,
+				expected: "1.2\n1.2.0\n1.2.1\n1.10\n",
+			},
+			{
+				filename: "missing-segments-error",
+				contents: "1.2.1\n1.2.0\n1.10\n",
+				args:     []string{"--missing-segments", "error"},
+				success:  false,
+			},
+			{
+				filename: "missing-segments-error-same-counts",
+				contents: "1.2.1\n1.10.0\n1.2.0\n",
+				args:     []string{"--missing-segments", "error"},
+				success:  true,
+				expected: "1.2.0\n1.2.1\n1.10.0\n",
+			},
+			{
+				filename: "unknown-missing-segments",
+				contents: "1.2.1\n",
+				args:     []string{"--missing-segments", "unknown"},
+				success:  false,
+			},
+			{
+				filename: "stable",
+				contents: "v1.0.0\nrelease-1.0.0\n0.9\n1.0\n",
+				args:     []string{"--prefix", "[a-z-]*", "--stable"},
+				success:  true,
+				expected: "0.9\nv1.0.0\nrelease-1.0.0\n1.0\n",
+			},
+			{
+				filename: "tie-break-lexical",
+				contents: "v1.0.0\nrelease-1.0.0\n0.9\n1.0\n",
+				args:     []string{"--prefix", "[a-z-]*", "--tie-break", "lexical"},
+				success:  true,
+				expected: "0.9\n1.0\nrelease-1.0.0\nv1.0.0\n",
+			},
+			{
+				filename: "tie-break-suffix",
+				contents: "1.0.0-2\n1.0.0-10\n0.9.0-3\n1.0.0-1\n",
+				args:     []string{"--suffix", `-\d+`, "--tie-break", "suffix", "--reverse"},
+				success:  true,
+				expected: "1.0.0-2\n1.0.0-10\n1.0.0-1\n0.9.0-3\n",
+			},
+			{
+				filename: "unknown-tie-break",
+				contents: "1.0.0\n",
+				args:     []string{"--tie-break", "unknown"},
+				success:  false,
+			},
+			{
+				filename: "suffix-key",
+				contents: "1.21.3-r4\n1.21.3-r12\n1.21.2-r20\n1.21.3-r1\n",
+				args:     []string{"--suffix", `-r(?P<rev>\d+)`, "--suffix-key", "rev"},
+				success:  true,
+				expected: "1.21.2-r20\n1.21.3-r1\n1.21.3-r4\n1.21.3-r12\n",
+			},
+			{
+				filename: "suffix-keys",
+				contents: "2.3.1-build57\n2.3.1-build123\n2.3.1-alpha7\n",
+				args:     []string{"--suffix", `-(?P<kind>[a-z]+)(?P<num>\d+)`, "--suffix-key", "kind:lexical,num"},
+				success:  true,
+				expected: "2.3.1-alpha7\n2.3.1-build57\n2.3.1-build123\n",
+			},
+			{
+				filename: "unknown-suffix-key",
+				contents: "1.21.3-r4\n",
+				args:     []string{"--suffix", `-r(?P<rev>\d+)`, "--suffix-key", "build"},
+				success:  false,
+			},
+			{
+				filename: "group-by",
+				contents: "web/v0.9.1\napi/v1.2.0\nweb/v0.10.0\napi/v1.10.0\n",
+				args:     []string{"--prefix", "(?P<component>[a-z-]+)/v", "--group-by", "component"},
+				success:  true,
+				expected: "api/v1.2.0\napi/v1.10.0\nweb/v0.9.1\nweb/v0.10.0\n",
+			},
+			{
+				filename: "group-by-reverse",
+				contents: "web/v0.9.1\napi/v1.2.0\nweb/v0.10.0\napi/v1.10.0\n",
+				args:     []string{"--prefix", "(?P<component>[a-z-]+)/v", "--group-by", "component", "--reverse"},
+				success:  true,
+				expected: "api/v1.10.0\napi/v1.2.0\nweb/v0.10.0\nweb/v0.9.1\n",
+			},
+			{
+				filename: "unknown-group-by",
+				contents: "api/v1.2.0\n",
+				args:     []string{"--prefix", "(?P<component>[a-z-]+)/v", "--group-by", "service"},
+				success:  false,
+			},
+			{
+				filename: "long-segments",
+				contents: "1.20231017123045123456\n1.9223372036854775807\n1.123456789012345678901234567890\n1.9\n",
+				args:     []string{"--strict"},
+				success:  true,
+				expected: "1.9\n1.9223372036854775807\n1.20231017123045123456\n1.123456789012345678901234567890\n",
+			},
+			{
+				filename: "buffer-size",
+				contents: "0.2.0\n0.0.1\n0.10.0\n1.0.0-a\n0.0.2\n0.1.0\n",
+				args:     []string{"--buffer-size", "1b"},
+				success:  true,
+				expected: "0.0.1\n0.0.2\n0.1.0\n0.2.0\n0.10.0\n",
+			},
+			{
+				filename: "buffer-size-parallel",
+				contents: "0.2.0\n0.0.1\n0.10.0\n1.0.0-a\n0.0.2\n0.1.0\n",
+				args:     []string{"-S", "20b", "--parallel", "2", "--reverse", "--output", "json"},
+				success:  true,
+				expected: `["0.10.0","0.2.0","0.1.0","0.0.2","0.0.1"]`,
+			},
+			{
+				filename: "parallel",
+				contents: "0.2.0\n0.0.1\n0.10.0\n0.0.2\n0.1.0\n",
+				args:     []string{"--parallel", "3"},
+				success:  true,
+				expected: "0.0.1\n0.0.2\n0.1.0\n0.2.0\n0.10.0\n",
+			},
+			{
+				filename: "buffer-size-missing-segments-error",
+				contents: "0.2.0\n0.0.1\n0.10\n",
+				args:     []string{"--buffer-size", "1b", "--missing-segments", "error"},
+				success:  false,
+			},
+			{
+				filename: "invalid-buffer-size",
+				contents: "0.2.0\n",
+				args:     []string{"--buffer-size", "1X"},
+				success:  false,
+			},
+			{
+				filename: "invalid-parallel",
+				contents: "0.2.0\n",
+				args:     []string{"--parallel", "0"},
+				success:  false,
+			},
+			{
+				filename: "key",
+				contents: "nginx 1.25.3 amd64\ncurl 8.4.0 arm64\nnginx 1.9.0 amd64\nbroken\n",
+				args:     []string{"-k", "2"},
+				success:  true,
+				expected: "nginx 1.9.0 amd64\nnginx 1.25.3 amd64\ncurl 8.4.0 arm64\n",
+			},
+			{
+				filename: "keys-with-separator",
+				contents: "web,v1.2.0\napi,v1.10.0\napi,v1.2.0\ndb,v1.2.0\n",
+				args:     []string{"-t", ",", "-k", "2", "-k", "1:lexical", "--prefix", "v"},
+				success:  true,
+				expected: "api,v1.2.0\ndb,v1.2.0\nweb,v1.2.0\napi,v1.10.0\n",
+			},
+			{
+				filename: "keys-with-buffer-size",
+				contents: "web,1.2.0\napi,1.10.0\napi,1.2.0\ndb,1.2.0\n",
+				args:     []string{"--field-separator", ",", "--key", "2", "--key", "1:lexical", "--buffer-size", "1b", "--reverse"},
+				success:  true,
+				expected: "api,1.10.0\nweb,1.2.0\ndb,1.2.0\napi,1.2.0\n",
+			},
+			{
+				filename: "batch-size",
+				contents: "0.2.0\n0.0.1\n0.10.0\n0.0.2\n1.0.0\n0.3.0\n",
+				args:     []string{"--buffer-size", "1b", "--batch-size", "2"},
+				success:  true,
+				expected: "0.0.1\n0.0.2\n0.2.0\n0.3.0\n0.10.0\n1.0.0\n",
+			},
+			{
+				filename: "invalid-batch-size",
+				contents: "0.2.0\n",
+				args:     []string{"--batch-size", "1"},
+				success:  false,
+			},
+			{
+				filename: "key-satisfies",
+				contents: "nginx 1.25.3\ncurl 8.4.0\nbash 5.2.15\n",
+				args:     []string{"-k", "2", "--satisfies", "<6"},
+				success:  true,
+				expected: "nginx 1.25.3\nbash 5.2.15\n",
+			},
+			{
+				filename: "key-strict",
+				contents: "nginx 1.25.3\ncurl\n",
+				args:     []string{"-k", "2", "--strict"},
+				success:  false,
+			},
+			{
+				filename: "invalid-key",
+				contents: "nginx 1.25.3\n",
+				args:     []string{"-k", "0"},
+				success:  false,
+			},
+			{
+				filename: "lexical-key-only",
+				contents: "nginx 1.25.3\n",
+				args:     []string{"-k", "1:lexical"},
+				success:  false,
+			},
+			{
+				filename: "extract",
+				contents: "myapp-1.10.2-linux-amd64.tar.gz\nmyapp-1.9.0-linux-amd64.tar.gz\nREADME.md\nmyapp-1.10.0-darwin-arm64.tar.gz\n",
+				args:     []string{"--extract"},
+				success:  true,
+				expected: "myapp-1.9.0-linux-amd64.tar.gz\nmyapp-1.10.0-darwin-arm64.tar.gz\nmyapp-1.10.2-linux-amd64.tar.gz\n",
+			},
+			{
+				filename: "extract-only-version",
+				contents: "myapp-1.10.2-linux-amd64.tar.gz\nmyapp-1.9.0-linux-amd64.tar.gz\nmyapp-1.10.0-darwin-arm64.tar.gz\n",
+				args:     []string{"--extract", "--only-version", "--reverse"},
+				success:  true,
+				expected: "1.10.2\n1.10.0\n1.9.0\n",
+			},
+			{
+				filename: "extract-pattern",
+				contents: "2023-10-01 INFO deployed v1.10.0\n2023-10-02 INFO deployed v1.2.0\n2023-10-03 INFO restarted\n",
+				args:     []string{"--extract-pattern", `deployed (?P<version>v\S+)`, "--prefix", "v"},
+				success:  true,
+				expected: "2023-10-02 INFO deployed v1.2.0\n2023-10-01 INFO deployed v1.10.0\n",
+			},
+			{
+				filename: "extract-key-only-version",
+				contents: "2023-10-01 myapp-1.10.0.tar.gz\n2023-09-01 myapp-1.2.0.tar.gz\n",
+				args:     []string{"-k", "2", "--extract", "--only-version"},
+				success:  true,
+				expected: "1.2.0\n1.10.0\n",
+			},
+			{
+				filename: "extract-strict",
+				contents: "myapp-1.10.2.tar.gz\nREADME.md\n",
+				args:     []string{"--extract", "--strict"},
+				success:  false,
+			},
+			{
+				filename: "extract-pattern-without-group",
+				contents: "myapp-1.10.2.tar.gz\n",
+				args:     []string{"--extract-pattern", `myapp-([0-9.]+)`},
+				success:  false,
+			},
+			{
+				filename: "invalid-first",
+				contents: "1.10\nb\n1.2\na\n",
+				args:     []string{"--invalid", "first"},
+				success:  true,
+				expected: "b\na\n1.2\n1.10\n",
+			},
+			{
+				filename: "invalid-last",
+				contents: "1.10\nb\n1.2\na\n",
+				args:     []string{"--invalid", "last", "-r"},
+				success:  true,
+				expected: "1.10\n1.2\nb\na\n",
+			},
+			{
+				filename: "invalid-last-lexical",
+				contents: "1.10\nb\n1.2\na\n",
+				args:     []string{"--invalid", "last:lexical"},
+				success:  true,
+				expected: "1.2\n1.10\na\nb\n",
+			},
+			{
+				filename: "invalid-first-json",
+				contents: "1.10\nb\n1.2\n",
+				args:     []string{"--invalid", "first", "-o", "json"},
+				success:  true,
+				expected: `["b","1.2","1.10"]`,
+			},
+			{
+				filename: "invalid-drop",
+				contents: "1.10\nb\n1.2\n",
+				args:     []string{"--invalid", "drop"},
+				success:  true,
+				expected: "1.2\n1.10\n",
+			},
+			{
+				filename: "invalid-error",
+				contents: "1.10\nb\n1.2\n",
+				args:     []string{"--invalid", "error"},
+				success:  false,
+			},
+			{
+				filename: "report-invalid-unknown",
+				contents: "1.10\n",
+				args:     []string{"--report-invalid", "xml"},
+				success:  false,
+			},
+			{
+				filename: "invalid-unknown",
+				contents: "1.10\n",
+				args:     []string{"--invalid", "drop:lexical"},
+				success:  false,
+			},
+			{
+				filename: "invalid-with-strict",
+				contents: "1.10\n",
+				args:     []string{"--invalid", "last", "--strict"},
+				success:  false,
+			},
 			{
 				filename: "with-invalid-strict",
 				contents: "0.2.0\nv0.3.0\n0.0.1\n0.10.0\n1.0.0-a\n0.0.2\n",
@@ -166,6 +575,132 @@ func TestExecute(t *testing.T) {
 		}
 	})
 
+	t.Run("WithInvalidStderr", func(t *testing.T) {
+		stdin := bytes.NewBufferString("1.10\nb\n1.2\na\n")
+		stdout := new(bytes.Buffer)
+		stderr := new(bytes.Buffer)
+		args := []string{"--invalid", "stderr"}
+
+		if assert.NoError(t, Execute("HEAD", stdin, stdout, stderr, args)) {
+			assert.Equal(t, "1.2\n1.10\n", stdout.String())
+			assert.Equal(t, "<stdin>:2: \"b\": segment 1 is not numeric\n<stdin>:4: \"a\": segment 1 is not numeric\n", stderr.String())
+		}
+	})
+
+	t.Run("WithReportInvalid", func(t *testing.T) {
+		cases := []struct {
+			input    string
+			args     []string
+			expected string
+			stderr   string
+		}{
+			{
+				input:    "1.10\n1.2.x\n1.2\n",
+				args:     []string{"--report-invalid", "text"},
+				expected: "1.2\n1.10\n",
+				stderr:   "<stdin>:2: \"1.2.x\": segment 3 is not numeric\n",
+			},
+			{
+				input:    "1.10\n1.2.x\n1.2\n",
+				args:     []string{"--report-invalid", "json", "--invalid", "last"},
+				expected: "1.2\n1.10\n1.2.x\n",
+				stderr:   `{"file":"<stdin>","line":2,"version":"1.2.x","reason":"segment 3 is not numeric"}` + "\n",
+			},
+			{
+				input:    `["1.2.x","1.10"]`,
+				args:     []string{"--report-invalid", "json", "-i", "json"},
+				expected: "1.10\n",
+				stderr:   `{"file":"<stdin>","index":0,"version":"1.2.x","reason":"segment 3 is not numeric"}` + "\n",
+			},
+			{
+				input:    "v1.10\nvabc\n",
+				args:     []string{"--report-invalid", "text", "--prefix", "v"},
+				expected: "v1.10\n",
+				stderr:   "<stdin>:2: \"vabc\": segment 1 is not numeric\n",
+			},
+			{
+				input:    "nginx 1.25.3\ncurl 8.x\n",
+				args:     []string{"--report-invalid", "json", "-k", "2"},
+				expected: "nginx 1.25.3\n",
+				stderr:   `{"file":"<stdin>","line":2,"version":"curl 8.x","reason":"field 2: segment 2 is not numeric"}` + "\n",
+			},
+			{
+				input:    "x,1.2,3\nx,1.2\n",
+				args:     []string{"--report-invalid", "text", "-t", ",", "-k", "2", "-k", "3"},
+				expected: "x,1.2,3\n",
+				stderr:   "<stdin>:2: \"x,1.2\": field 3: missing\n",
+			},
+			{
+				input:    "a 1.2 x\n",
+				args:     []string{"--report-invalid", "text", "-k", "2,3"},
+				expected: "",
+				stderr:   "<stdin>:1: \"a 1.2 x\": fields 2-3: segment 2 is not numeric\n",
+			},
+			{
+				input:    "myapp-1.10.tar.gz\nREADME.md\n",
+				args:     []string{"--report-invalid", "text", "--extract"},
+				expected: "myapp-1.10.tar.gz\n",
+				stderr:   "<stdin>:2: \"README.md\": version is not found\n",
+			},
+			{
+				input:    `["1.10","1.2.x"]`,
+				args:     []string{"--report-invalid", "text", "-i", "json"},
+				expected: "1.10\n",
+				stderr:   "<stdin>[1]: \"1.2.x\": segment 3 is not numeric\n",
+			},
+		}
+
+		for _, tt := range cases {
+			t.Run(fmt.Sprintf("%q", tt.args), func(t *testing.T) {
+				stdin := bytes.NewBufferString(tt.input)
+				stdout := new(bytes.Buffer)
+				stderr := new(bytes.Buffer)
+
+				if assert.NoError(t, Execute("HEAD", stdin, stdout, stderr, tt.args)) {
+					assert.Equal(t, tt.expected, stdout.String())
+					assert.Equal(t, tt.stderr, stderr.String())
+				}
+			})
+		}
+	})
+
+	t.Run("WithStrictDiagnostic", func(t *testing.T) {
+		stdin := bytes.NewBufferString("1.10\n1.2.x\n1.2\n")
+		stdout := new(bytes.Buffer)
+		stderr := new(bytes.Buffer)
+
+		err := Execute("HEAD", stdin, stdout, stderr, []string{"--strict"})
+		if assert.Error(t, err) {
+			assert.Equal(t, `<stdin>:2: "1.2.x": segment 3 is not numeric`, err.Error())
+			assert.Equal(t, 1, strings.Count(stdout.String()+stderr.String(), "segment 3 is not numeric"))
+		}
+	})
+
+	t.Run("WithMissingSegmentsErrorDiagnostic", func(t *testing.T) {
+		stdin := bytes.NewBufferString("1.10\n1.2.x\n1.2.0\n")
+		stdout := new(bytes.Buffer)
+		stderr := new(bytes.Buffer)
+
+		err := Execute("HEAD", stdin, stdout, stderr, []string{"--missing-segments", "error"})
+		if assert.Error(t, err) {
+			assert.Equal(t, `<stdin>:3: "1.2.0": segment count is different from "1.10" at <stdin>:1`, err.Error())
+		}
+	})
+
+	t.Run("WithRegisteredScheme", func(t *testing.T) {
+		registerReversedNumericScheme.Do(func() {
+			vsort.RegisterScheme("test-reversed-numeric", reversedNumericScheme{})
+		})
+
+		stdin := bytes.NewBufferString("1\n10\n2\n")
+		stdout := new(bytes.Buffer)
+		args := []string{"--scheme", "test-reversed-numeric"}
+
+		if assertSuccessWithNoStderr(t, "HEAD", stdin, stdout, new(bytes.Buffer), args) {
+			assert.Equal(t, "10\n2\n1\n", stdout.String())
+		}
+	})
+
 	t.Run("WithStdin", func(t *testing.T) {
 		cases := []struct {
 			input    string
@@ -196,6 +731,24 @@ func TestExecute(t *testing.T) {
 	})
 }
 
+// reversedNumericScheme is a scheme of integers in reverse order for testing
+type reversedNumericScheme struct{}
+
+// registerReversedNumericScheme registers reversedNumericScheme once since the registry is global
+var registerReversedNumericScheme sync.Once
+
+func (reversedNumericScheme) Parse(v string) (interface{}, error) {
+	return strconv.Atoi(v)
+}
+
+func (reversedNumericScheme) Compare(x, y interface{}) int {
+	return y.(int) - x.(int)
+}
+
+func (reversedNumericScheme) Format(x interface{}) string {
+	return strconv.Itoa(x.(int))
+}
+
 func createTempfile(filename, contents string) (*os.File, error) {
 	f, err := ioutil.TempFile("", filename)
 	if err != nil {
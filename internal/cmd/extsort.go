@@ -0,0 +1,432 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"container/heap"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// stringOverhead is the approximate memory used by a string other than its bytes
+const stringOverhead = 16
+
+// lineOverhead is the approximate memory used by a parsed line other than its text and keys
+const lineOverhead = 64
+
+// versionOverhead is the approximate memory used by a parsed version other than its text.
+// It includes the value parsed by scheme, which is assumed to be the text and a few slice headers.
+const versionOverhead = 192
+
+// defaultBatchSize is the default number of runs merged at once, which is the same as NMERGE of GNU sort
+const defaultBatchSize = 16
+
+// mergeSorter sorts parsed lines by sorting runs of them and merging the runs.
+// Runs are spilled to temporary files when the buffer is full, and sorted in parallel.
+// Lines are parsed only once unless they are spilled.
+// At most batchSize runs are merged at once, so runs are merged in multiple passes if there are more.
+type mergeSorter struct {
+	sorter    *lineSorter
+	chunkSize int64 // 0 means unlimited
+	parallel  int
+	batchSize int
+
+	chunk     []parsedLine
+	chunkUsed int64
+	runs      []*sortedRun
+
+	sem chan struct{}
+	wg  sync.WaitGroup
+	mu  sync.Mutex
+	err error
+}
+
+// sortedRun is sorted lines kept in memory or spilled to a temporary file
+type sortedRun struct {
+	lines []parsedLine
+	path  string
+}
+
+// newMergeSorter returns mergeSorter using bufferSize bytes (0 means unlimited), parallel goroutines
+// and merging batchSize (at least 2) runs at once
+func newMergeSorter(s *lineSorter, bufferSize int64, parallel, batchSize int) *mergeSorter {
+	return &mergeSorter{
+		sorter: s,
+		// runs sorted in parallel share the buffer
+		chunkSize: bufferSize / int64(parallel),
+		parallel:  parallel,
+		batchSize: batchSize,
+		sem:       make(chan struct{}, parallel),
+	}
+}
+
+// Add adds p to the current chunk, and spills the chunk when the buffer is full.
+// It returns an error if spilling a previous chunk failed.
+func (m *mergeSorter) Add(p parsedLine) error {
+	m.chunk = append(m.chunk, p)
+	m.chunkUsed += lineSize(p)
+	if m.chunkSize > 0 && m.chunkUsed >= m.chunkSize {
+		m.flush(true)
+	}
+
+	return m.firstErr()
+}
+
+// flush sorts the current chunk as a new run in background.
+// The run is spilled to a temporary file if spill is true.
+func (m *mergeSorter) flush(spill bool) {
+	r := new(sortedRun)
+	m.runs = append(m.runs, r)
+	chunk := m.chunk
+	m.chunk, m.chunkUsed = nil, 0
+
+	m.sem <- struct{}{}
+	m.wg.Add(1)
+	go func() {
+		defer func() {
+			<-m.sem
+			m.wg.Done()
+		}()
+
+		m.sorter.sortParsed(chunk)
+		if !spill {
+			r.lines = chunk
+			return
+		}
+
+		path, err := writeRun(func(emit func(string) error) error {
+			return emitAll(chunk, emit)
+		})
+		if err != nil {
+			m.setErr(fmt.Errorf("cannot spill sorted lines: %w", err))
+			return
+		}
+		r.path = path
+	}()
+}
+
+// Finish sorts the rest of lines and passes all lines to emit in sorted order
+func (m *mergeSorter) Finish(emit func(string) error) error {
+	// the last chunk is kept in memory, and split to be sorted in parallel
+	chunk := m.chunk
+	parts := m.parallel
+	if len(chunk) < parts {
+		parts = 1
+	}
+	for i := 0; i < parts; i++ {
+		m.chunk = chunk[len(chunk)*i/parts : len(chunk)*(i+1)/parts]
+		m.flush(false)
+	}
+	m.wg.Wait()
+	if err := m.firstErr(); err != nil {
+		return err
+	}
+
+	if len(m.runs) == 1 {
+		return emitAll(m.runs[0].lines, emit)
+	}
+	if err := m.reduce(); err != nil {
+		return err
+	}
+
+	return m.merge(m.runs, emit)
+}
+
+// reduce merges each batch of consecutive runs into a new run until at most batchSize runs are left.
+// Consecutive runs are merged to keep input order of equivalent versions.
+func (m *mergeSorter) reduce() error {
+	for len(m.runs) > m.batchSize {
+		var reduced []*sortedRun
+		for i := 0; i < len(m.runs); i += m.batchSize {
+			end := i + m.batchSize
+			if end > len(m.runs) {
+				end = len(m.runs)
+			}
+			batch := m.runs[i:end]
+			if len(batch) == 1 {
+				reduced = append(reduced, batch[0])
+				continue
+			}
+
+			path, err := writeRun(func(emit func(string) error) error {
+				return m.merge(batch, emit)
+			})
+			if err != nil {
+				// keep runs not merged yet to be removed by Close
+				m.runs = append(reduced, m.runs[i:]...)
+				return err
+			}
+			reduced = append(reduced, &sortedRun{path: path})
+
+			for _, r := range batch {
+				if e := r.remove(); e != nil && err == nil {
+					err = e
+				}
+			}
+			if err != nil {
+				m.runs = append(reduced, m.runs[end:]...)
+				return err
+			}
+		}
+		m.runs = reduced
+	}
+
+	return nil
+}
+
+// Close removes temporary files
+func (m *mergeSorter) Close() error {
+	m.wg.Wait()
+
+	var err error
+	for _, r := range m.runs {
+		if e := r.remove(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+// merge passes versions of runs to emit in sorted order
+func (m *mergeSorter) merge(runs []*sortedRun, emit func(string) error) error {
+	h := &runHeap{sorter: m.sorter}
+	for i, r := range runs {
+		c := &runCursor{index: i}
+		if r.path != "" {
+			f, err := os.Open(r.path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			c.reader = bufio.NewReader(f)
+		} else {
+			c.lines = r.lines
+		}
+
+		ok, err := c.next(m.sorter)
+		if err != nil {
+			return err
+		}
+		if ok {
+			h.cursors = append(h.cursors, c)
+		}
+	}
+	heap.Init(h)
+
+	for h.Len() > 0 {
+		c := h.cursors[0]
+		if err := emit(c.current.text); err != nil {
+			return err
+		}
+
+		ok, err := c.next(m.sorter)
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+
+	return nil
+}
+
+func (m *mergeSorter) setErr(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err == nil {
+		m.err = err
+	}
+}
+
+func (m *mergeSorter) firstErr() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.err
+}
+
+// remove removes the temporary file of r if spilled
+func (r *sortedRun) remove() error {
+	if r.path == "" {
+		return nil
+	}
+	path := r.path
+	r.path = ""
+
+	return os.Remove(path)
+}
+
+// writeRun writes sorted versions passed to emit by write to a temporary file, and returns the path
+func writeRun(write func(emit func(string) error) error) (string, error) {
+	f, err := ioutil.TempFile("", "vsort-")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// versions are quoted since they may contain newlines
+	w := bufio.NewWriter(f)
+	err = write(func(v string) error {
+		_, err := w.WriteString(strconv.Quote(v) + "\n")
+		return err
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// emitAll passes text of lines to emit in order
+func emitAll(lines []parsedLine, emit func(string) error) error {
+	for _, p := range lines {
+		if err := emit(p.text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lineSize returns the approximate memory used by p
+func lineSize(p parsedLine) int64 {
+	size := int64(len(p.text)) + lineOverhead
+	for _, v := range p.versions {
+		size += int64(len(v.String())) + versionOverhead
+	}
+	for _, t := range p.texts {
+		size += int64(len(t)) + stringOverhead
+	}
+
+	return size
+}
+
+// runCursor points the current line of a run
+type runCursor struct {
+	index   int
+	lines   []parsedLine
+	reader  *bufio.Reader
+	current parsedLine
+}
+
+// next moves c to the next line. It returns false at the end of run.
+// Lines read from a spilled run are parsed again.
+func (c *runCursor) next(s *lineSorter) (bool, error) {
+	if c.reader == nil {
+		if len(c.lines) == 0 {
+			return false, nil
+		}
+		c.current = c.lines[0]
+		c.lines = c.lines[1:]
+		return true, nil
+	}
+
+	line, err := c.reader.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	v, err := strconv.Unquote(strings.TrimSuffix(line, "\n"))
+	if err != nil {
+		return false, fmt.Errorf("broken temporary file: %w", err)
+	}
+
+	current, err := s.parse(v)
+	if err != nil {
+		return false, err
+	}
+	c.current = current
+
+	return true, nil
+}
+
+// runHeap is a min-heap of cursors ordered by the current versions.
+// Equivalent versions are ordered by index of runs to keep input order.
+type runHeap struct {
+	sorter  *lineSorter
+	cursors []*runCursor
+}
+
+func (h *runHeap) Len() int {
+	return len(h.cursors)
+}
+
+func (h *runHeap) Less(i, j int) bool {
+	x, y := h.cursors[i], h.cursors[j]
+	if r := h.sorter.compare(x.current, y.current); r != 0 {
+		return r < 0
+	}
+
+	return x.index < y.index
+}
+
+func (h *runHeap) Swap(i, j int) {
+	h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i]
+}
+
+func (h *runHeap) Push(x interface{}) {
+	h.cursors = append(h.cursors, x.(*runCursor))
+}
+
+func (h *runHeap) Pop() interface{} {
+	last := h.cursors[len(h.cursors)-1]
+	h.cursors = h.cursors[:len(h.cursors)-1]
+
+	return last
+}
+
+// parseBufferSize parses size like "sort -S". A number without suffix is in KiB.
+func parseBufferSize(size string) (int64, error) {
+	if size == "" {
+		return 0, nil
+	}
+
+	digits, suffix := size, ""
+	for i, c := range size {
+		if c < '0' || c > '9' {
+			digits, suffix = size[:i], size[i:]
+			break
+		}
+	}
+
+	units := map[string]int64{"": 1 << 10, "b": 1, "K": 1 << 10, "k": 1 << 10, "M": 1 << 20, "G": 1 << 30, "T": 1 << 40}
+	unit, ok := units[suffix]
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if !ok || err != nil {
+		return 0, fmt.Errorf("invalid buffer size: %q", size)
+	}
+	if n > (1<<63-1)/unit {
+		return 0, fmt.Errorf("buffer size is too large: %q", size)
+	}
+
+	return n * unit, nil
+}
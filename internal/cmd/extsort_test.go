@@ -0,0 +1,185 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/autopp/vsort/pkg/vsort"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSorter(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "vsort-test")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(tmp)
+	defer os.Setenv("TMPDIR", os.Getenv("TMPDIR"))
+	os.Setenv("TMPDIR", tmp)
+
+	r := rand.New(rand.NewSource(1))
+	versions := make([]string, 500)
+	for i := range versions {
+		versions[i] = fmt.Sprintf("%s%d.%d.%d", []string{"", "v", "release-"}[r.Intn(3)], r.Intn(3), r.Intn(5), r.Intn(5))
+	}
+
+	cases := []struct {
+		options    []vsort.Option
+		bufferSize int64
+		parallel   int
+		batchSize  int
+	}{
+		{bufferSize: 0, parallel: 1, batchSize: defaultBatchSize},
+		{bufferSize: 0, parallel: 4, batchSize: defaultBatchSize},
+		{bufferSize: 1, parallel: 1, batchSize: defaultBatchSize},
+		{bufferSize: 256, parallel: 1, batchSize: defaultBatchSize},
+		{bufferSize: 256, parallel: 3, batchSize: defaultBatchSize},
+		{options: []vsort.Option{vsort.WithOrder(vsort.Desc)}, bufferSize: 256, parallel: 3, batchSize: defaultBatchSize},
+		{options: []vsort.Option{vsort.WithTieBreak(vsort.TieBreakInput)}, bufferSize: 256, parallel: 3, batchSize: defaultBatchSize},
+		{options: []vsort.Option{vsort.WithTieBreak(vsort.TieBreakLexical), vsort.WithOrder(vsort.Desc)}, bufferSize: 100, parallel: 2, batchSize: defaultBatchSize},
+		{bufferSize: 1, parallel: 1, batchSize: 2},
+		{bufferSize: 1, parallel: 4, batchSize: 3},
+		{bufferSize: 256, parallel: 3, batchSize: 2},
+		{options: []vsort.Option{vsort.WithTieBreak(vsort.TieBreakInput), vsort.WithOrder(vsort.Desc)}, bufferSize: 1, parallel: 2, batchSize: 5},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%s,buffer=%d,parallel=%d,batch=%d", tt.options, tt.bufferSize, tt.parallel, tt.batchSize), func(t *testing.T) {
+			s, err := vsort.NewSorter(append([]vsort.Option{vsort.WithPrefix("[a-z-]*"), vsort.WithStable(true)}, tt.options...)...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			expected := make([]string, len(versions))
+			copy(expected, versions)
+			s.Sort(expected)
+
+			ls := &lineSorter{sorter: s}
+			ms := newMergeSorter(ls, tt.bufferSize, tt.parallel, tt.batchSize)
+			for _, v := range versions {
+				p, err := ls.parse(v)
+				if !assert.NoError(t, err) || !assert.NoError(t, ms.Add(p)) {
+					return
+				}
+			}
+			var actual []string
+			err = ms.Finish(func(v string) error {
+				actual = append(actual, v)
+				return nil
+			})
+			if assert.NoError(t, err) {
+				assert.Equal(t, expected, actual)
+			}
+
+			assert.NoError(t, ms.Close())
+			files, err := ioutil.ReadDir(tmp)
+			if assert.NoError(t, err) {
+				assert.Empty(t, files)
+			}
+		})
+	}
+}
+
+func TestMergeSorterSpillsNewlines(t *testing.T) {
+	s, err := vsort.NewSorter(vsort.WithScheme(vsort.Natural))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ls := &lineSorter{sorter: s}
+	ms := newMergeSorter(ls, 1, 1, 2)
+	defer ms.Close()
+	for _, v := range []string{"b\n2", "a\"1", "b\n1", ""} {
+		p, err := ls.parse(v)
+		if !assert.NoError(t, err) || !assert.NoError(t, ms.Add(p)) {
+			return
+		}
+	}
+
+	var actual []string
+	err = ms.Finish(func(v string) error {
+		actual = append(actual, v)
+		return nil
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"", "a\"1", "b\n1", "b\n2"}, actual)
+	}
+}
+
+func TestParseBufferSize(t *testing.T) {
+	cases := []struct {
+		size     string
+		expected int64
+		success  bool
+	}{
+		{size: "", expected: 0, success: true},
+		{size: "0", expected: 0, success: true},
+		{size: "10", expected: 10 << 10, success: true},
+		{size: "100b", expected: 100, success: true},
+		{size: "4K", expected: 4 << 10, success: true},
+		{size: "64M", expected: 64 << 20, success: true},
+		{size: "2G", expected: 2 << 30, success: true},
+		{size: "1T", expected: 1 << 40, success: true},
+		{size: "M", success: false},
+		{size: "1.5G", success: false},
+		{size: "10X", success: false},
+		{size: "-1K", success: false},
+		{size: "99999999999T", success: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q", tt.size), func(t *testing.T) {
+			actual, err := parseBufferSize(tt.size)
+			if tt.success {
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.expected, actual)
+				}
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestMergeSorterKeepsParsedLines(t *testing.T) {
+	s, err := vsort.NewSorter()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// lines in memory are not parsed again, so texts need not be versions
+	ms := newMergeSorter(&lineSorter{sorter: s}, 0, 2, defaultBatchSize)
+	defer ms.Close()
+	for _, v := range []string{"1.10", "1.2", "1.9"} {
+		x, err := s.Parse(v)
+		if !assert.NoError(t, err) || !assert.NoError(t, ms.Add(parsedLine{text: "line " + v, versions: []vsort.Version{x}})) {
+			return
+		}
+	}
+
+	var actual []string
+	err = ms.Finish(func(v string) error {
+		actual = append(actual, v)
+		return nil
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"line 1.2", "line 1.9", "line 1.10"}, actual)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/autopp/vsort/pkg/vsort"
+)
+
+// fieldKey is a range of fields compared as version or lexically
+type fieldKey struct {
+	start   int
+	end     int
+	lexical bool
+}
+
+// parseFieldKey parses key formatted as "FIELD[,FIELD2][:version|:lexical]". Fields are 1-origin.
+// Unlike sort(1), FIELD without FIELD2 is only the field instead of the rest of line.
+func parseFieldKey(key string) (fieldKey, error) {
+	k := fieldKey{}
+	fields := key
+	if i := strings.IndexByte(key, ':'); i >= 0 {
+		switch key[i+1:] {
+		case "version":
+		case "lexical":
+			k.lexical = true
+		default:
+			return fieldKey{}, fmt.Errorf("kind of key should be version or lexical: %q", key)
+		}
+		fields = key[:i]
+	}
+
+	start, end := fields, fields
+	if i := strings.IndexByte(fields, ','); i >= 0 {
+		start, end = fields[:i], fields[i+1:]
+	}
+
+	var err error
+	if k.start, err = strconv.Atoi(start); err != nil || k.start < 1 {
+		return fieldKey{}, fmt.Errorf("field of key should be positive number: %q", key)
+	}
+	if k.end, err = strconv.Atoi(end); err != nil || k.end < k.start {
+		return fieldKey{}, fmt.Errorf("end field of key should be number not less than start: %q", key)
+	}
+
+	return k, nil
+}
+
+// fields returns the description of fields of k (e.g. "field 2" or "fields 2-3")
+func (k fieldKey) fields() string {
+	if k.start == k.end {
+		return fmt.Sprintf("field %d", k.start)
+	}
+
+	return fmt.Sprintf("fields %d-%d", k.start, k.end)
+}
+
+// keyError is an error of a version key of a line
+type keyError struct {
+	key fieldKey
+	err error
+}
+
+func (e *keyError) Error() string {
+	return fmt.Sprintf("%s: %s", e.key.fields(), e.err)
+}
+
+func (e *keyError) Unwrap() error {
+	return e.err
+}
+
+// lineSorter sorts lines by keys in fields, or by the whole lines if no keys are given.
+// If extract is true, versions are found in the keys instead of the keys being versions.
+type lineSorter struct {
+	sorter    vsort.Sorter
+	reverse   bool
+	separator string
+	keys      []fieldKey
+	extract   bool
+}
+
+// parsedLine is a line and its keys
+type parsedLine struct {
+	text string
+	// versions[i] is the i-th version key, and texts[i] is the i-th lexical key
+	versions []vsort.Version
+	texts    []string
+}
+
+// newLineSorter returns lineSorter. Empty separator means runs of white spaces.
+func newLineSorter(s vsort.Sorter, reverse bool, separator string, keys []fieldKey, extract bool) (*lineSorter, error) {
+	if len(keys) > 0 {
+		hasVersion := false
+		for _, k := range keys {
+			hasVersion = hasVersion || !k.lexical
+		}
+		if !hasVersion {
+			return nil, errors.New("at least one key should be compared as version")
+		}
+	}
+
+	return &lineSorter{sorter: s, reverse: reverse, separator: separator, keys: keys, extract: extract}, nil
+}
+
+// parseVersion parses text as version, or extracts version from text
+func (l *lineSorter) parseVersion(text string) (vsort.Version, error) {
+	if l.extract {
+		return l.sorter.Extract(text)
+	}
+
+	return l.sorter.Parse(text)
+}
+
+// parse parses keys of line. It returns an error when a version key is invalid, which is *keyError if keys are given.
+func (l *lineSorter) parse(line string) (parsedLine, error) {
+	p := parsedLine{text: line}
+	if len(l.keys) == 0 {
+		v, err := l.parseVersion(line)
+		if err != nil {
+			return parsedLine{}, err
+		}
+		p.versions = []vsort.Version{v}
+		return p, nil
+	}
+
+	var fields []string
+	sep := l.separator
+	if sep == "" {
+		fields = strings.Fields(line)
+		sep = " "
+	} else {
+		fields = strings.Split(line, sep)
+	}
+
+	for _, k := range l.keys {
+		var text string
+		if k.start <= len(fields) {
+			end := k.end
+			if end > len(fields) {
+				end = len(fields)
+			}
+			text = strings.Join(fields[k.start-1:end], sep)
+		}
+
+		if k.lexical {
+			p.texts = append(p.texts, text)
+			continue
+		}
+		if k.start > len(fields) {
+			return parsedLine{}, &keyError{key: k, err: errors.New("missing")}
+		}
+		v, err := l.parseVersion(text)
+		if err != nil {
+			return parsedLine{}, &keyError{key: k, err: err}
+		}
+		p.versions = append(p.versions, v)
+	}
+
+	return p, nil
+}
+
+// version returns the first version key of p
+func (p parsedLine) version() vsort.Version {
+	return p.versions[0]
+}
+
+// compare compares x and y by keys in order. Order of sorter is applied to lexical keys too.
+func (l *lineSorter) compare(x, y parsedLine) int {
+	// the whole line is the only version key if no keys are given
+	n := len(l.keys)
+	if n == 0 {
+		n = 1
+	}
+
+	vi, ti := 0, 0
+	for i := 0; i < n; i++ {
+		var r int
+		if len(l.keys) > 0 && l.keys[i].lexical {
+			r = strings.Compare(x.texts[ti], y.texts[ti])
+			if l.reverse {
+				r = -r
+			}
+			ti++
+		} else {
+			switch {
+			case l.sorter.Less(x.versions[vi], y.versions[vi]):
+				r = -1
+			case l.sorter.Less(y.versions[vi], x.versions[vi]):
+				r = 1
+			}
+			vi++
+		}
+		if r != 0 {
+			return r
+		}
+	}
+
+	return 0
+}
+
+// SortE sorts lines. Lines equivalent in all keys keep input order.
+func (l *lineSorter) SortE(lines []string) error {
+	if len(l.keys) == 0 && !l.extract {
+		return l.sorter.SortE(lines)
+	}
+
+	parsed := make([]parsedLine, len(lines))
+	for i, line := range lines {
+		p, err := l.parse(line)
+		if err != nil {
+			return err
+		}
+		parsed[i] = p
+	}
+
+	l.sortParsed(parsed)
+	for i, p := range parsed {
+		lines[i] = p.text
+	}
+
+	return nil
+}
+
+// sortParsed sorts parsed lines. Lines equivalent in all keys keep input order.
+func (l *lineSorter) sortParsed(lines []parsedLine) {
+	sort.SliceStable(lines, func(i, j int) bool {
+		return l.compare(lines[i], lines[j]) < 0
+	})
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/autopp/vsort/pkg/vsort"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFieldKey(t *testing.T) {
+	cases := []struct {
+		key      string
+		expected fieldKey
+		success  bool
+	}{
+		{key: "2", expected: fieldKey{start: 2, end: 2}, success: true},
+		{key: "2,3", expected: fieldKey{start: 2, end: 3}, success: true},
+		{key: "1:lexical", expected: fieldKey{start: 1, end: 1, lexical: true}, success: true},
+		{key: "1,2:version", expected: fieldKey{start: 1, end: 2}, success: true},
+		{key: "", success: false},
+		{key: "0", success: false},
+		{key: "a", success: false},
+		{key: "3,2", success: false},
+		{key: "2,", success: false},
+		{key: "2:numeric", success: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q", tt.key), func(t *testing.T) {
+			actual, err := parseFieldKey(tt.key)
+			if tt.success {
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.expected, actual)
+				}
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestLineSorterSortE(t *testing.T) {
+	cases := []struct {
+		lines     []string
+		reverse   bool
+		separator string
+		keys      []string
+		expected  []string
+		success   bool
+	}{
+		{
+			lines:    []string{"nginx 1.25.3 amd64", "curl  8.4.0  arm64", "nginx 1.9.0 amd64"},
+			keys:     []string{"2"},
+			expected: []string{"nginx 1.9.0 amd64", "nginx 1.25.3 amd64", "curl  8.4.0  arm64"},
+			success:  true,
+		},
+		{
+			lines:     []string{"web,1.2.0", "api,1.10.0", "api,1.2.0", "db,1.2.0"},
+			separator: ",",
+			keys:      []string{"2", "1:lexical"},
+			expected:  []string{"api,1.2.0", "db,1.2.0", "web,1.2.0", "api,1.10.0"},
+			success:   true,
+		},
+		{
+			lines:     []string{"web,1.2.0", "api,1.10.0", "api,1.2.0", "db,1.2.0"},
+			reverse:   true,
+			separator: ",",
+			keys:      []string{"2", "1:lexical"},
+			expected:  []string{"api,1.10.0", "web,1.2.0", "db,1.2.0", "api,1.2.0"},
+			success:   true,
+		},
+		{
+			lines:     []string{"a.1.2.so", "b.1.10.so", "c.1.3.so", "d.0.99.so"},
+			separator: ".",
+			keys:      []string{"2,3"},
+			expected:  []string{"d.0.99.so", "a.1.2.so", "c.1.3.so", "b.1.10.so"},
+			success:   true,
+		},
+		{
+			lines:    []string{"x 1.0.0", "y 0.1.0", "z 1.0.0", "w 1.0"},
+			keys:     []string{"2"},
+			expected: []string{"y 0.1.0", "x 1.0.0", "z 1.0.0", "w 1.0"},
+			success:  true,
+		},
+		{
+			lines:   []string{"nginx 1.25.3", "curl"},
+			keys:    []string{"2"},
+			success: false,
+		},
+		{
+			lines:   []string{"nginx 1.25.3", "curl x"},
+			keys:    []string{"2"},
+			success: false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q(%q,%q)", tt.lines, tt.separator, tt.keys), func(t *testing.T) {
+			order := vsort.WithOrder(vsort.Asc)
+			if tt.reverse {
+				order = vsort.WithOrder(vsort.Desc)
+			}
+			s, err := vsort.NewSorter(order)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			keys := make([]fieldKey, len(tt.keys))
+			for i, k := range tt.keys {
+				keys[i], err = parseFieldKey(k)
+				if !assert.NoError(t, err) {
+					return
+				}
+			}
+			ls, err := newLineSorter(s, tt.reverse, tt.separator, keys, false)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			err = ls.SortE(tt.lines)
+			if tt.success {
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.expected, tt.lines)
+				}
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewLineSorterWithoutVersionKey(t *testing.T) {
+	s, err := vsort.NewSorter()
+	if assert.NoError(t, err) {
+		_, err := newLineSorter(s, false, "", []fieldKey{{start: 1, end: 1, lexical: true}}, false)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/autopp/vsort/pkg/vsort"
+)
+
+// entry is a version string read from input and its location
+type entry struct {
+	text string
+	file string
+	// line is 1-origin line number in lines input, and index is 0-origin index of array in JSON input.
+	// line is 0 in JSON input.
+	line  int
+	index int
+}
+
+// location returns the location of e formatted as "FILE:LINE" or "FILE[INDEX]"
+func (e entry) location() string {
+	if e.line > 0 {
+		return e.file + ":" + strconv.Itoa(e.line)
+	}
+
+	return e.file + "[" + strconv.Itoa(e.index) + "]"
+}
+
+// invalidVersion is the diagnostic of an invalid version in input
+type invalidVersion struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Index   *int   `json:"index,omitempty"`
+	Version string `json:"version"`
+	Reason  string `json:"reason"`
+	entry   entry
+}
+
+func newInvalidVersion(e entry, err error) *invalidVersion {
+	d := &invalidVersion{File: e.file, Line: e.line, Version: e.text, entry: e}
+	if e.line == 0 {
+		index := e.index
+		d.Index = &index
+	}
+	// the version (or the key of it) is already shown as the whole line
+	var fields string
+	var ke *keyError
+	if errors.As(err, &ke) {
+		fields = ke.key.fields() + ": "
+		err = ke.err
+	}
+	var pe *vsort.ParseError
+	if errors.As(err, &pe) {
+		err = pe.Err
+	}
+	d.Reason = fields + err.Error()
+
+	return d
+}
+
+// Error returns the diagnostic formatted as `FILE:LINE: "VERSION": REASON`
+func (d *invalidVersion) Error() string {
+	return fmt.Sprintf("%s: %q: %s", d.entry.location(), d.Version, d.Reason)
+}
+
+// reportInvalid writes d to w in format "text" or "json". JSON diagnostics are written one per line.
+func reportInvalid(w io.Writer, format string, d *invalidVersion) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(d)
+	}
+
+	_, err := fmt.Fprintln(w, d.Error())
+	return err
+}
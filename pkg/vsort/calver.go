@@ -0,0 +1,222 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WithCalVerFormat represents format template of CalVer scheme (e.g. "YYYY.0M.MICRO")
+type WithCalVerFormat string
+
+func (f WithCalVerFormat) apply(s *sorter) error {
+	s.calverFormat = string(f)
+
+	return nil
+}
+
+func (f WithCalVerFormat) String() string {
+	return "calver-format=" + string(f)
+}
+
+// calVerField is a field of CalVer format
+type calVerField struct {
+	name    string
+	pattern string
+	width   int
+	min     int
+	max     int
+}
+
+// calVerFields is fields of CalVer format (https://calver.org/).
+// Longer names are placed before shorter ones sharing the same prefix.
+var calVerFields = []calVerField{
+	{name: "YYYY", pattern: `[0-9]{4}`, width: 4, min: 0, max: -1},
+	{name: "YY", pattern: `0|[1-9][0-9]*`, min: 0, max: -1},
+	{name: "0Y", pattern: `[0-9]{2}|[1-9][0-9]{2,}`, width: 2, min: 0, max: -1},
+	{name: "MM", pattern: `[1-9][0-9]?`, min: 1, max: 12},
+	{name: "0M", pattern: `[0-9]{2}`, width: 2, min: 1, max: 12},
+	{name: "WW", pattern: `[1-9][0-9]?`, min: 1, max: 53},
+	{name: "0W", pattern: `[0-9]{2}`, width: 2, min: 1, max: 53},
+	{name: "DD", pattern: `[1-9][0-9]?`, min: 1, max: 31},
+	{name: "0D", pattern: `[0-9]{2}`, width: 2, min: 1, max: 31},
+	{name: "MAJOR", pattern: `[0-9]+`, min: 0, max: -1},
+	{name: "MINOR", pattern: `[0-9]+`, min: 0, max: -1},
+	{name: "MICRO", pattern: `[0-9]+`, min: 0, max: -1},
+}
+
+// calVerScheme is calendar versioning configured by format template
+type calVerScheme struct {
+	format  string
+	pattern *regexp.Regexp
+	fields  []calVerField
+	// literals[i] is the literal text before fields[i], and the last one is after all fields
+	literals []string
+}
+
+func newCalVerScheme(format string) (*calVerScheme, error) {
+	if format == "" {
+		return nil, errors.New("calver scheme requires format")
+	}
+
+	c := &calVerScheme{format: format}
+	pattern := "^"
+	literal := ""
+	rest := format
+	for rest != "" {
+		found := false
+		for _, f := range calVerFields {
+			if strings.HasPrefix(rest, f.name) {
+				c.fields = append(c.fields, f)
+				c.literals = append(c.literals, literal)
+				pattern += regexp.QuoteMeta(literal) + "(" + f.pattern + ")"
+				literal = ""
+				rest = rest[len(f.name):]
+				found = true
+				break
+			}
+		}
+		if !found {
+			literal += rest[:1]
+			rest = rest[1:]
+		}
+	}
+	c.literals = append(c.literals, literal)
+	pattern += regexp.QuoteMeta(literal)
+	if len(c.fields) == 0 {
+		return nil, fmt.Errorf("calver format contains no fields: %q", format)
+	}
+
+	r, err := regexp.Compile(pattern + "$")
+	if err != nil {
+		return nil, err
+	}
+	c.pattern = r
+
+	return c, nil
+}
+
+func (c *calVerScheme) Parse(v string) (interface{}, error) {
+	m := c.pattern.FindStringSubmatch(v)
+	if m == nil {
+		return nil, fmt.Errorf("does not match format %q", c.format)
+	}
+
+	nums := make([]string, len(c.fields))
+	year, month, day := -1, -1, -1
+	for i, f := range c.fields {
+		nums[i] = normalizeDigits(m[i+1])
+
+		switch f.name {
+		case "YYYY":
+			year = lastFourDigits(nums[i])
+		case "YY", "0Y":
+			year = 2000 + lastFourDigits(nums[i])
+		}
+		if f.max < 0 {
+			// unbounded fields are compared as digit strings of any length
+			continue
+		}
+
+		n, _ := strconv.Atoi(nums[i])
+		if n < f.min || n > f.max {
+			return nil, fmt.Errorf("%s should be in %d-%d", f.name, f.min, f.max)
+		}
+
+		switch f.name {
+		case "MM", "0M":
+			month = n
+		case "DD", "0D":
+			day = n
+		}
+	}
+
+	if month > 0 && day > 0 && day > daysIn(month, year) {
+		return nil, fmt.Errorf("day %d does not exist in month %d", day, month)
+	}
+
+	return nums, nil
+}
+
+func (c *calVerScheme) Compare(x, y interface{}) int {
+	nums1 := x.([]string)
+	nums2 := y.([]string)
+
+	for i := range nums1 {
+		if r := compareDigits(nums1[i], nums2[i]); r != 0 {
+			return r
+		}
+	}
+
+	return 0
+}
+
+func (c *calVerScheme) Format(x interface{}) string {
+	nums := x.([]string)
+
+	var b strings.Builder
+	for i, f := range c.fields {
+		b.WriteString(c.literals[i])
+		for j := len(nums[i]); j < f.width; j++ {
+			b.WriteByte('0')
+		}
+		b.WriteString(nums[i])
+	}
+	b.WriteString(c.literals[len(c.fields)])
+
+	return b.String()
+}
+
+func (c *calVerScheme) appendKey(b []byte, x interface{}) []byte {
+	for _, num := range x.([]string) {
+		b = appendDigitsKey(b, num)
+	}
+
+	return b
+}
+
+func (c *calVerScheme) segments(x interface{}) []string {
+	return x.([]string)
+}
+
+// lastFourDigits returns the number of the last 4 digits of digit string s.
+// It is enough to know whether a year is leap since 10000 is a multiple of 400.
+func lastFourDigits(s string) int {
+	if len(s) > 4 {
+		s = s[len(s)-4:]
+	}
+	n, _ := strconv.Atoi(s)
+
+	return n
+}
+
+// daysIn returns the number of days in month of year. Negative year means unknown year.
+func daysIn(month, year int) int {
+	switch month {
+	case 2:
+		if year < 0 || (year%4 == 0 && (year%100 != 0 || year%400 == 0)) {
+			return 29
+		}
+		return 28
+	case 4, 6, 9, 11:
+		return 30
+	default:
+		return 31
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalVerCompare(t *testing.T) {
+	cases := []struct {
+		format   string
+		v1       string
+		v2       string
+		expected int
+	}{
+		{format: "YY.0M", v1: "22.04", v2: "22.04", expected: 0},
+		{format: "YY.0M", v1: "22.04", v2: "22.10", expected: -1},
+		{format: "YY.0M", v1: "23.04", v2: "22.10", expected: 1},
+		{format: "YYYY.MM.MICRO", v1: "2023.10.1", v2: "2023.9.12", expected: 1},
+		{format: "YYYY.MM.MICRO", v1: "2023.10.2", v2: "2023.10.10", expected: -1},
+		{format: "YYYY.0M.0D", v1: "2023.01.31", v2: "2023.02.01", expected: -1},
+		{format: "YYYY-0W", v1: "2023-09", v2: "2023-10", expected: -1},
+		{format: "YYYY.MICRO", v1: "2023.123456789012345678901234567890", v2: "2023.99999999999999999999", expected: 1},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q<=>%q(%s)", tt.v1, tt.v2, tt.format), func(t *testing.T) {
+			s, err := NewSorter(WithScheme(CalVer), WithCalVerFormat(tt.format))
+			if assert.NoError(t, err) {
+				actual, err := s.Compare(tt.v1, tt.v2)
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestCalVerIsValid(t *testing.T) {
+	cases := []struct {
+		format   string
+		version  string
+		expected bool
+	}{
+		{format: "YY.0M", version: "22.04", expected: true},
+		{format: "YY.0M", version: "22.4", expected: false},
+		{format: "YY.0M", version: "22.13", expected: false},
+		{format: "YY.0M", version: "22.00", expected: false},
+		{format: "YY.MM", version: "22.4", expected: true},
+		{format: "YY.MM", version: "22.04", expected: false},
+		{format: "YY.MM", version: "06.4", expected: false},
+		{format: "0Y.MM", version: "06.4", expected: true},
+		{format: "YYYY.0M.MICRO", version: "2023.10.1", expected: true},
+		{format: "YYYY.0M.MICRO", version: "23.10.1", expected: false},
+		{format: "YYYY.0M.MICRO", version: "2023.10", expected: false},
+		{format: "YYYY.0M.0D", version: "2024.02.29", expected: true},
+		{format: "YYYY.0M.0D", version: "2023.02.29", expected: false},
+		{format: "YYYY.0M.0D", version: "2023.04.31", expected: false},
+		{format: "YYYY.0M.0D", version: "2023.12.31", expected: true},
+		{format: "YYYY.WW", version: "2023.53", expected: true},
+		{format: "YYYY.WW", version: "2023.54", expected: false},
+		{format: "vYYYY.MM", version: "v2023.1", expected: true},
+		{format: "YYYY.MICRO", version: "2023.123456789012345678901234567890", expected: true},
+		{format: "YY.0M.0D", version: "123456789012345678901234567890.02.29", expected: false},
+		{format: "YY.0M.0D", version: "123456789012345678901234567892.02.29", expected: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q(%s)", tt.version, tt.format), func(t *testing.T) {
+			s, err := NewSorter(WithScheme(CalVer), WithCalVerFormat(tt.format))
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, s.IsValid(tt.version))
+			}
+		})
+	}
+}
+
+func TestNewCalVerSorter(t *testing.T) {
+	cases := []struct {
+		options []Option
+		success bool
+	}{
+		{options: []Option{WithScheme(CalVer), WithCalVerFormat("YYYY.0M")}, success: true},
+		{options: []Option{WithScheme(CalVer)}, success: false},
+		{options: []Option{WithScheme(CalVer), WithCalVerFormat("1.2")}, success: false},
+		{options: []Option{WithCalVerFormat("YYYY.0M")}, success: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%s", tt.options), func(t *testing.T) {
+			_, err := NewSorter(tt.options...)
+			if tt.success {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,635 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Constraint is a set of versions expressed like ">=1.2.0, <2.0.0 || ^3.1".
+// Comma or whitespace separated terms are intersected, and alternatives separated by "||" are united.
+//
+// Supported terms are comparisons ("=", "!=", ">", ">=", "<" and "<="),
+// tilde ranges ("~1.4" means ">=1.4.0 <1.5.0"), caret ranges ("^0.3.1" means ">=0.3.1 <0.4.0")
+// and wildcards ("1.2.x", "1.2.*" or "*").
+// "*" is always a wildcard even in schemes accepting it as a version.
+// Versions in terms may omit prefix and suffix of Sorter.
+// Tilde ranges, caret ranges and wildcards are available only in schemes consisting of numeric segments.
+// In schemes with pre-releases (semver and gomod), they exclude pre-releases of the upper bound (e.g. "^1.2" means ">=1.2.0 <2.0.0-0"),
+// and so do "<" and ">=" with partial versions (e.g. "<2" means "<2.0.0-0").
+type Constraint struct {
+	sorter    *sorter
+	expr      string
+	intervals []interval
+}
+
+// interval is a range of versions. nil bound means unbounded.
+type interval struct {
+	lower *bound
+	upper *bound
+}
+
+// bound is an end of interval
+type bound struct {
+	value     interface{}
+	inclusive bool
+}
+
+// segmentBuilder is implemented by schemes whose versions can be built from numeric segments
+type segmentBuilder interface {
+	segmenter
+	fromSegments(segments []string) (interface{}, error)
+}
+
+// preReleaser is implemented by schemes whose pre-releases precede the release
+type preReleaser interface {
+	// lowestPreRelease returns the lowest pre-release of release x
+	lowestPreRelease(x interface{}) interface{}
+}
+
+var constraintOperatorPattern = regexp.MustCompile(`^(!=|==|>=|<=|=|>|<|~|\^)?\s*(.*)$`)
+
+var partialVersionPattern = regexp.MustCompile(`^(?:[0-9]+|[xX*])(?:\.(?:[0-9]+|[xX*]))*$`)
+
+// ParseConstraint parses expr as Constraint whose versions are compared by s
+func (s *sorter) ParseConstraint(expr string) (*Constraint, error) {
+	c := &Constraint{sorter: s, expr: expr}
+
+	for _, alternative := range strings.Split(expr, "||") {
+		terms, err := splitConstraintTerms(alternative)
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q: %s", expr, err)
+		}
+
+		intervals := []interval{{}}
+		for _, term := range terms {
+			ti, err := s.parseConstraintTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %s", expr, err)
+			}
+			intervals = s.intersectIntervals(intervals, ti)
+		}
+		c.intervals = append(c.intervals, intervals...)
+	}
+
+	return c, nil
+}
+
+// splitConstraintTerms splits s into terms separated by commas or whitespaces.
+// An operator separated from its version by whitespaces (e.g. ">= 1.2") is joined.
+func splitConstraintTerms(s string) ([]string, error) {
+	fields := strings.FieldsFunc(s, func(c rune) bool {
+		return c == ',' || c == ' ' || c == '\t'
+	})
+
+	var terms []string
+	operator := ""
+	for _, f := range fields {
+		if strings.Trim(f, "!=<>~^") == "" {
+			if operator != "" {
+				return nil, fmt.Errorf("operator %q is not followed by version", operator)
+			}
+			operator = f
+			continue
+		}
+		terms = append(terms, operator+f)
+		operator = ""
+	}
+	if operator != "" {
+		return nil, fmt.Errorf("operator %q is not followed by version", operator)
+	}
+	if len(terms) == 0 {
+		return nil, errors.New("empty alternative")
+	}
+
+	return terms, nil
+}
+
+// parseConstraintTerm parses a term of constraint into intervals
+func (s *sorter) parseConstraintTerm(term string) ([]interval, error) {
+	m := constraintOperatorPattern.FindStringSubmatch(term)
+	operator, operand := m[1], m[2]
+
+	// exact version, which is tried after "*" since some schemes (e.g. natural) accept it
+	if x, err := s.parseOperand(operand); operand != "*" && err == nil {
+		exact := &bound{value: x, inclusive: true}
+		switch operator {
+		case "", "=", "==":
+			return []interval{{lower: exact, upper: exact}}, nil
+		case "!=":
+			return []interval{{upper: exclusive(exact)}, {lower: exclusive(exact)}}, nil
+		case ">":
+			return []interval{{lower: exclusive(exact)}}, nil
+		case ">=":
+			return []interval{{lower: exact}}, nil
+		case "<":
+			return []interval{{upper: exclusive(exact)}}, nil
+		case "<=":
+			return []interval{{upper: exact}}, nil
+		}
+
+		// "~" and "^" use numeric segments of the version
+		sg, ok := s.scheme.(segmentBuilder)
+		if !ok {
+			return nil, fmt.Errorf("%q is not supported by the scheme", term)
+		}
+		segments := sg.segments(x)
+		upper, err := s.rangeUpper(operator, segments, len(segments))
+		if err != nil {
+			return nil, fmt.Errorf("%q: %s", term, err)
+		}
+		return []interval{{lower: exact, upper: upper}}, nil
+	}
+
+	// partial version (e.g. "1.2", "1.2.x" or "*")
+	positions, ok := s.parsePartialOperand(operand)
+	if !ok {
+		return nil, fmt.Errorf("invalid version in %q", term)
+	}
+	if positions[0] == "" {
+		// "*" matches any version
+		switch operator {
+		case "!=", ">", "<":
+			return nil, nil
+		default:
+			return []interval{{}}, nil
+		}
+	}
+	if _, ok := s.scheme.(segmentBuilder); !ok {
+		return nil, fmt.Errorf("%q is not supported by the scheme", term)
+	}
+
+	specified := positions
+	for i, p := range positions {
+		if p == "" {
+			specified = positions[:i]
+			break
+		}
+	}
+
+	lowerValue, err := s.fromSegments(specified, len(positions))
+	if err != nil {
+		return nil, fmt.Errorf("%q: %s", term, err)
+	}
+	lower := &bound{value: lowerValue, inclusive: true}
+	// "<N" and ">=N" are split at the lowest pre-release of N so that "<2" is the same as "<=1.x"
+	lowest := lower
+	if pr, ok := s.scheme.(preReleaser); ok {
+		lowest = &bound{value: pr.lowestPreRelease(lowerValue), inclusive: true}
+	}
+
+	wildcardUpper, err := s.rangeUpper("", specified, len(positions))
+	if err != nil {
+		return nil, fmt.Errorf("%q: %s", term, err)
+	}
+
+	switch operator {
+	case "", "=", "==":
+		return []interval{{lower: lower, upper: wildcardUpper}}, nil
+	case "!=":
+		return []interval{{upper: exclusive(lower)}, {lower: inclusive(wildcardUpper)}}, nil
+	case ">":
+		return []interval{{lower: inclusive(wildcardUpper)}}, nil
+	case ">=":
+		return []interval{{lower: lowest}}, nil
+	case "<":
+		return []interval{{upper: exclusive(lowest)}}, nil
+	case "<=":
+		return []interval{{upper: wildcardUpper}}, nil
+	default:
+		upper, err := s.rangeUpper(operator, specified, len(positions))
+		if err != nil {
+			return nil, fmt.Errorf("%q: %s", term, err)
+		}
+		return []interval{{lower: lower, upper: upper}}, nil
+	}
+}
+
+// parseOperand parses v as a version with or without prefix and suffix
+func (s *sorter) parseOperand(v string) (interface{}, error) {
+	if x, err := s.scheme.Parse(v); err == nil {
+		return x, nil
+	}
+
+	parsed, err := s.Parse(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.value, nil
+}
+
+// parsePartialOperand parses v as a partial version and returns its segments.
+// Wildcard segments are returned as empty strings.
+func (s *sorter) parsePartialOperand(v string) ([]string, bool) {
+	if !partialVersionPattern.MatchString(v) && s.prefix != nil {
+		if loc := s.prefix.FindStringIndex(v); loc != nil {
+			v = v[loc[1]:]
+		}
+	}
+	if !partialVersionPattern.MatchString(v) {
+		return nil, false
+	}
+
+	positions := strings.Split(v, ".")
+	wildcard := false
+	for i, p := range positions {
+		if p == "x" || p == "X" || p == "*" {
+			wildcard = true
+			positions[i] = ""
+		} else if wildcard {
+			// digits after wildcard (e.g. "1.x.3")
+			return nil, false
+		}
+	}
+
+	return positions, true
+}
+
+// rangeUpper returns the exclusive upper bound of tilde range, caret range or wildcard ("" operator)
+// whose specified numeric segments are segments.
+// In schemes with pre-releases, the bound is the lowest pre-release of the bumped version (e.g. "<2.0.0-0" for "^1.2")
+// so that pre-releases of the bumped version are excluded.
+func (s *sorter) rangeUpper(operator string, segments []string, length int) (*bound, error) {
+	var bumped int
+	switch operator {
+	case "":
+		bumped = len(segments) - 1
+	case "~":
+		bumped = 0
+		if len(segments) >= 2 {
+			bumped = 1
+		}
+	case "^":
+		bumped = len(segments) - 1
+		for i, seg := range segments {
+			if strings.TrimLeft(seg, "0") != "" {
+				bumped = i
+				break
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown operator %q", operator)
+	}
+
+	if bumped < 0 {
+		return nil, nil
+	}
+
+	upper := make([]string, bumped+1)
+	copy(upper, segments)
+	upper[bumped] = incrementDigits(upper[bumped])
+
+	x, err := s.fromSegments(upper, length)
+	if err != nil {
+		return nil, err
+	}
+	if pr, ok := s.scheme.(preReleaser); ok {
+		x = pr.lowestPreRelease(x)
+	}
+
+	return &bound{value: x}, nil
+}
+
+// fromSegments builds a version from segments padded with zeros to length
+func (s *sorter) fromSegments(segments []string, length int) (interface{}, error) {
+	padded := make([]string, length)
+	for i := range padded {
+		padded[i] = "0"
+	}
+	copy(padded, segments)
+
+	return s.scheme.(segmentBuilder).fromSegments(padded)
+}
+
+// Check reports whether v satisfies c. v should be parsed by the same Sorter as c.
+// Zero value of Version (and Version returned by Sorter.EmptyVersion) satisfies no constraint.
+func (c *Constraint) Check(v Version) bool {
+	if v.isEmpty() {
+		return false
+	}
+
+	for _, i := range c.intervals {
+		if c.sorter.contains(i, v.value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the expression of c
+func (c *Constraint) String() string {
+	return c.expr
+}
+
+// Intersect returns a constraint satisfied by versions satisfying both c and other.
+// other should be parsed by the same Sorter as c.
+func (c *Constraint) Intersect(other *Constraint) *Constraint {
+	return c.sorter.newConstraint(c.sorter.intersectIntervals(c.intervals, other.intervals))
+}
+
+// Union returns a constraint satisfied by versions satisfying c or other.
+// other should be parsed by the same Sorter as c.
+func (c *Constraint) Union(other *Constraint) *Constraint {
+	intervals := make([]interval, 0, len(c.intervals)+len(other.intervals))
+	intervals = append(intervals, c.intervals...)
+	intervals = append(intervals, other.intervals...)
+
+	return c.sorter.newConstraint(intervals)
+}
+
+// Complement returns a constraint satisfied by versions not satisfying c
+func (c *Constraint) Complement() *Constraint {
+	return c.sorter.newConstraint(c.sorter.complementIntervals(c.intervals))
+}
+
+// IsEmpty reports whether no version satisfies c
+func (c *Constraint) IsEmpty() bool {
+	return len(c.intervals) == 0
+}
+
+// Overlaps reports whether some versions satisfy both c and other.
+// other should be parsed by the same Sorter as c.
+func (c *Constraint) Overlaps(other *Constraint) bool {
+	return !c.Intersect(other).IsEmpty()
+}
+
+// IsSubsetOf reports whether all versions satisfying c also satisfy other.
+// other should be parsed by the same Sorter as c.
+func (c *Constraint) IsSubsetOf(other *Constraint) bool {
+	return c.Intersect(other.Complement()).IsEmpty()
+}
+
+// Equals reports whether c and other are satisfied by the same versions.
+// other should be parsed by the same Sorter as c.
+func (c *Constraint) Equals(other *Constraint) bool {
+	return c.IsSubsetOf(other) && other.IsSubsetOf(c)
+}
+
+// newConstraint returns a constraint consisting of intervals, whose expression is built from them
+func (s *sorter) newConstraint(intervals []interval) *Constraint {
+	merged := s.mergeIntervals(intervals)
+
+	var alternatives []string
+	for _, i := range merged {
+		alternatives = append(alternatives, s.formatInterval(i))
+	}
+	expr := strings.Join(alternatives, " || ")
+	if len(merged) == 0 {
+		expr = "!=*"
+	}
+
+	return &Constraint{sorter: s, expr: expr, intervals: merged}
+}
+
+// formatInterval returns the expression of i
+func (s *sorter) formatInterval(i interval) string {
+	if i.lower != nil && i.upper != nil && i.lower.inclusive && i.upper.inclusive && s.scheme.Compare(i.lower.value, i.upper.value) == 0 {
+		return "=" + s.scheme.Format(i.lower.value)
+	}
+
+	var terms []string
+	if i.lower != nil {
+		operator := ">"
+		if i.lower.inclusive {
+			operator = ">="
+		}
+		terms = append(terms, operator+s.scheme.Format(i.lower.value))
+	}
+	if i.upper != nil {
+		operator := "<"
+		if i.upper.inclusive {
+			operator = "<="
+		}
+		terms = append(terms, operator+s.scheme.Format(i.upper.value))
+	}
+	if len(terms) == 0 {
+		return "*"
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// mergeIntervals returns sorted disjoint intervals covering the same versions as intervals
+func (s *sorter) mergeIntervals(intervals []interval) []interval {
+	sorted := make([]interval, 0, len(intervals))
+	for _, i := range intervals {
+		if !s.isEmptyInterval(i) {
+			sorted = append(sorted, i)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return s.compareLower(sorted[i].lower, sorted[j].lower) < 0
+	})
+
+	var merged []interval
+	for _, i := range sorted {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if s.touches(last.upper, i.lower) {
+				if s.compareUpper(i.upper, last.upper) > 0 {
+					last.upper = i.upper
+				}
+				continue
+			}
+		}
+		merged = append(merged, i)
+	}
+
+	return merged
+}
+
+// complementIntervals returns intervals covering versions not covered by intervals
+func (s *sorter) complementIntervals(intervals []interval) []interval {
+	merged := s.mergeIntervals(intervals)
+	if len(merged) == 0 {
+		return []interval{{}}
+	}
+
+	var result []interval
+	if merged[0].lower != nil {
+		result = append(result, interval{upper: flip(merged[0].lower)})
+	}
+	for k := 0; k+1 < len(merged); k++ {
+		result = append(result, interval{lower: flip(merged[k].upper), upper: flip(merged[k+1].lower)})
+	}
+	if last := merged[len(merged)-1]; last.upper != nil {
+		result = append(result, interval{lower: flip(last.upper)})
+	}
+
+	return result
+}
+
+// compareLower compares lower bounds. Unbounded is the lowest.
+func (s *sorter) compareLower(b1, b2 *bound) int {
+	switch {
+	case b1 == nil && b2 == nil:
+		return 0
+	case b1 == nil:
+		return -1
+	case b2 == nil:
+		return 1
+	}
+
+	if r := s.scheme.Compare(b1.value, b2.value); r != 0 {
+		return r
+	}
+	// inclusive lower bound is lower than exclusive one
+	return compareBool(!b1.inclusive, !b2.inclusive)
+}
+
+// compareUpper compares upper bounds. Unbounded is the highest.
+func (s *sorter) compareUpper(b1, b2 *bound) int {
+	switch {
+	case b1 == nil && b2 == nil:
+		return 0
+	case b1 == nil:
+		return 1
+	case b2 == nil:
+		return -1
+	}
+
+	if r := s.scheme.Compare(b1.value, b2.value); r != 0 {
+		return r
+	}
+	// inclusive upper bound is higher than exclusive one
+	return compareBool(b1.inclusive, b2.inclusive)
+}
+
+// touches reports whether an interval ending at upper and one starting at lower overlap or are adjacent
+func (s *sorter) touches(upper, lower *bound) bool {
+	if upper == nil || lower == nil {
+		return true
+	}
+
+	r := s.scheme.Compare(lower.value, upper.value)
+	return r < 0 || (r == 0 && (lower.inclusive || upper.inclusive))
+}
+
+// contains reports whether i contains x
+func (s *sorter) contains(i interval, x interface{}) bool {
+	if i.lower != nil {
+		r := s.scheme.Compare(x, i.lower.value)
+		if r < 0 || (r == 0 && !i.lower.inclusive) {
+			return false
+		}
+	}
+	if i.upper != nil {
+		r := s.scheme.Compare(x, i.upper.value)
+		if r > 0 || (r == 0 && !i.upper.inclusive) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// intersectIntervals returns the intersection of two unions of intervals
+func (s *sorter) intersectIntervals(is1, is2 []interval) []interval {
+	var result []interval
+	for _, i1 := range is1 {
+		for _, i2 := range is2 {
+			i := interval{
+				lower: s.maxLower(i1.lower, i2.lower),
+				upper: s.minUpper(i1.upper, i2.upper),
+			}
+			if !s.isEmptyInterval(i) {
+				result = append(result, i)
+			}
+		}
+	}
+
+	return result
+}
+
+// maxLower returns the tighter of two lower bounds
+func (s *sorter) maxLower(b1, b2 *bound) *bound {
+	if b1 == nil {
+		return b2
+	}
+	if b2 == nil {
+		return b1
+	}
+
+	r := s.scheme.Compare(b1.value, b2.value)
+	if r > 0 || (r == 0 && !b1.inclusive) {
+		return b1
+	}
+	return b2
+}
+
+// minUpper returns the tighter of two upper bounds
+func (s *sorter) minUpper(b1, b2 *bound) *bound {
+	if b1 == nil {
+		return b2
+	}
+	if b2 == nil {
+		return b1
+	}
+
+	r := s.scheme.Compare(b1.value, b2.value)
+	if r < 0 || (r == 0 && !b1.inclusive) {
+		return b1
+	}
+	return b2
+}
+
+// isEmptyInterval reports whether no version is contained in i
+func (s *sorter) isEmptyInterval(i interval) bool {
+	if i.lower == nil || i.upper == nil {
+		return false
+	}
+
+	r := s.scheme.Compare(i.lower.value, i.upper.value)
+	return r > 0 || (r == 0 && !(i.lower.inclusive && i.upper.inclusive))
+}
+
+// flip returns the bound at the same version with the opposite inclusiveness
+func flip(b *bound) *bound {
+	return &bound{value: b.value, inclusive: !b.inclusive}
+}
+
+func inclusive(b *bound) *bound {
+	if b == nil {
+		return nil
+	}
+	return &bound{value: b.value, inclusive: true}
+}
+
+func exclusive(b *bound) *bound {
+	if b == nil {
+		return nil
+	}
+	return &bound{value: b.value, inclusive: false}
+}
+
+// incrementDigits returns the digit string s plus one
+func incrementDigits(s string) string {
+	digits := []byte(s)
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] < '9' {
+			digits[i]++
+			return string(digits)
+		}
+		digits[i] = '0'
+	}
+
+	return "1" + string(digits)
+}
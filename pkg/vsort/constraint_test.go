@@ -0,0 +1,478 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	type versionCase struct {
+		version  string
+		expected bool
+	}
+	cases := []struct {
+		options    []Option
+		constraint string
+		versions   []versionCase
+	}{
+		{
+			constraint: ">=1.2.0, <2.0.0",
+			versions: []versionCase{
+				{version: "1.1.9", expected: false},
+				{version: "1.2.0", expected: true},
+				{version: "1.10.3", expected: true},
+				{version: "2.0.0", expected: false},
+			},
+		},
+		{
+			constraint: ">= 1.2.0 < 2.0.0",
+			versions: []versionCase{
+				{version: "1.2.0", expected: true},
+				{version: "2.0.0", expected: false},
+			},
+		},
+		{
+			constraint: "!=1.3.0",
+			versions: []versionCase{
+				{version: "1.2.0", expected: true},
+				{version: "1.3.0", expected: false},
+				{version: "1.4.0", expected: true},
+			},
+		},
+		{
+			constraint: "1.2.x",
+			versions: []versionCase{
+				{version: "1.1.9", expected: false},
+				{version: "1.2.0", expected: true},
+				{version: "1.2.15", expected: true},
+				{version: "1.3.0", expected: false},
+			},
+		},
+		{
+			constraint: ">1.2.*",
+			versions: []versionCase{
+				{version: "1.2.15", expected: false},
+				{version: "1.3.0", expected: true},
+			},
+		},
+		{
+			constraint: "<=1.x",
+			versions: []versionCase{
+				{version: "1.99.0", expected: true},
+				{version: "2.0.0", expected: false},
+			},
+		},
+		{
+			constraint: ">=1.2 <2",
+			versions: []versionCase{
+				{version: "1.1.9", expected: false},
+				{version: "1.2.0", expected: true},
+				{version: "2.0.0", expected: false},
+			},
+		},
+		{
+			constraint: "*",
+			versions: []versionCase{
+				{version: "0.0.0", expected: true},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer)},
+			constraint: "~1.4",
+			versions: []versionCase{
+				{version: "1.3.9", expected: false},
+				{version: "1.4.0", expected: true},
+				{version: "1.4.12", expected: true},
+				{version: "1.5.0", expected: false},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer)},
+			constraint: "~1.4.2",
+			versions: []versionCase{
+				{version: "1.4.1", expected: false},
+				{version: "1.4.2", expected: true},
+				{version: "1.5.0", expected: false},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer)},
+			constraint: "^0.3.1",
+			versions: []versionCase{
+				{version: "0.3.0", expected: false},
+				{version: "0.3.1", expected: true},
+				{version: "0.3.9", expected: true},
+				{version: "0.4.0", expected: false},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer)},
+			constraint: "^1.2.3",
+			versions: []versionCase{
+				{version: "1.2.3", expected: true},
+				{version: "1.9.0", expected: true},
+				{version: "2.0.0", expected: false},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer)},
+			constraint: "^0.0.3",
+			versions: []versionCase{
+				{version: "0.0.3", expected: true},
+				{version: "0.0.4", expected: false},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer)},
+			constraint: "<1.0.0 || >=2.0.0-rc.1, !=2.1.0",
+			versions: []versionCase{
+				{version: "0.9.0", expected: true},
+				{version: "1.0.0", expected: false},
+				{version: "2.0.0-rc.1", expected: true},
+				{version: "2.1.0", expected: false},
+				{version: "3.0.0", expected: true},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer), WithPrefix("v")},
+			constraint: ">=v1.2 <2",
+			versions: []versionCase{
+				{version: "v1.1.0", expected: false},
+				{version: "v1.2.0", expected: true},
+				{version: "v2.0.0", expected: false},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer)},
+			constraint: "^1.2",
+			versions: []versionCase{
+				{version: "1.2.0-rc.1", expected: false},
+				{version: "1.3.0-alpha", expected: true},
+				{version: "1.9.0", expected: true},
+				{version: "2.0.0-0", expected: false},
+				{version: "2.0.0-rc.1", expected: false},
+				{version: "2.0.0", expected: false},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer)},
+			constraint: "~1.4",
+			versions: []versionCase{
+				{version: "1.4.3-beta.2", expected: true},
+				{version: "1.5.0-alpha", expected: false},
+				{version: "1.5.0", expected: false},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer)},
+			constraint: "1.2.x",
+			versions: []versionCase{
+				{version: "1.2.15", expected: true},
+				{version: "1.3.0-alpha", expected: false},
+				{version: "1.3.0", expected: false},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer)},
+			constraint: ">1.2.x",
+			versions: []versionCase{
+				{version: "1.2.15", expected: false},
+				{version: "1.3.0-alpha", expected: true},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer)},
+			constraint: ">=1.2 <2",
+			versions: []versionCase{
+				{version: "1.2.0", expected: true},
+				{version: "1.9.0", expected: true},
+				{version: "2.0.0-rc.1", expected: false},
+				{version: "2.0.0", expected: false},
+			},
+		},
+		{
+			options:    []Option{WithScheme(SemVer)},
+			constraint: ">=2",
+			versions: []versionCase{
+				{version: "1.9.0", expected: false},
+				{version: "2.0.0-rc.1", expected: true},
+				{version: "2.0.0", expected: true},
+			},
+		},
+		{
+			options:    []Option{WithScheme(GoMod)},
+			constraint: "^v1.2",
+			versions: []versionCase{
+				{version: "v1.9.0", expected: true},
+				{version: "v2.0.0-rc.1", expected: false},
+				{version: "v2.0.0-20231017000000-abcdefabcdef", expected: false},
+			},
+		},
+		{
+			options:    []Option{WithScheme(Debian)},
+			constraint: ">=2.30~rc1 <2.31",
+			versions: []versionCase{
+				{version: "2.30~beta1", expected: false},
+				{version: "2.30-1ubuntu3", expected: true},
+				{version: "2.31", expected: false},
+			},
+		},
+	}
+
+	genSubtestName := func(constraint string, options []Option, c versionCase) string {
+		return fmt.Sprintf("%q(%s)in%q", c.version, options, constraint)
+	}
+
+	for _, c := range cases {
+		s, err := NewSorter(c.options...)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		constraint, err := s.ParseConstraint(c.constraint)
+		if !assert.NoError(t, err, c.constraint) {
+			continue
+		}
+		assert.Equal(t, c.constraint, constraint.String())
+
+		for _, tt := range c.versions {
+			t.Run(genSubtestName(c.constraint, c.options, tt), func(t *testing.T) {
+				v, err := s.Parse(tt.version)
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.expected, constraint.Check(v))
+				}
+			})
+		}
+	}
+}
+
+func TestConstraintCheckZeroVersion(t *testing.T) {
+	s, err := NewSorter()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, expr := range []string{"*", ">=1.0", "!=1.0"} {
+		t.Run(expr, func(t *testing.T) {
+			c := mustParseConstraint(t, s, expr)
+			assert.False(t, c.Check(Version{}))
+		})
+	}
+}
+
+func TestConstraintPartialPreRelease(t *testing.T) {
+	s, err := NewSorter(WithScheme(SemVer))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, mustParseConstraint(t, s, "<2").Equals(mustParseConstraint(t, s, "<=1.x")))
+	assert.True(t, mustParseConstraint(t, s, "^1.2").Equals(mustParseConstraint(t, s, ">=1.2.0 <2")))
+	assert.True(t, mustParseConstraint(t, s, ">=2").Equals(mustParseConstraint(t, s, "<2").Complement()))
+}
+
+func TestParseConstraintError(t *testing.T) {
+	cases := []struct {
+		options    []Option
+		constraint string
+	}{
+		{constraint: ""},
+		{constraint: ">="},
+		{constraint: "1.0 ||"},
+		{constraint: "=>1.0"},
+		{constraint: "1.x.3"},
+		{constraint: "abc"},
+		{options: []Option{WithScheme(Debian)}, constraint: "~1.4"},
+		{options: []Option{WithScheme(Natural)}, constraint: "^1.4"},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q(%s)", tt.constraint, tt.options), func(t *testing.T) {
+			s, err := NewSorter(tt.options...)
+			if assert.NoError(t, err) {
+				_, err := s.ParseConstraint(tt.constraint)
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestConstraintAlgebra(t *testing.T) {
+	cases := []struct {
+		options      []Option
+		c1           string
+		c2           string
+		intersection string
+		union        string
+		overlaps     bool
+		subset       bool
+		superset     bool
+	}{
+		{
+			options:      []Option{WithScheme(SemVer)},
+			c1:           "^1.2",
+			c2:           ">=1.0.0 <2.0.0",
+			intersection: ">=1.2.0 <2.0.0-0",
+			union:        ">=1.0.0 <2.0.0",
+			overlaps:     true,
+			subset:       true,
+			superset:     false,
+		},
+		{
+			c1:           ">=1.0 <2",
+			c2:           ">=2 <3",
+			intersection: "!=*",
+			union:        ">=1.0 <3",
+			overlaps:     false,
+			subset:       false,
+			superset:     false,
+		},
+		{
+			c1:           ">1.0 <2",
+			c2:           "<1.0 || >=3",
+			intersection: "!=*",
+			union:        "<1.0 || >1.0 <2 || >=3",
+			overlaps:     false,
+			subset:       false,
+			superset:     false,
+		},
+		{
+			c1:           "!=1.5",
+			c2:           "1.5",
+			intersection: "!=*",
+			union:        "*",
+			overlaps:     false,
+			subset:       false,
+			superset:     false,
+		},
+		{
+			c1:           "1.x",
+			c2:           "~1.4 || 1.6.2",
+			intersection: ">=1.4 <1.5 || =1.6.2",
+			union:        ">=1.0 <2.0",
+			overlaps:     true,
+			subset:       false,
+			superset:     true,
+		},
+		{
+			c1:           ">=1.2 <1.2",
+			c2:           ">=1.0",
+			intersection: "!=*",
+			union:        ">=1.0",
+			overlaps:     false,
+			subset:       true,
+			superset:     false,
+		},
+		{
+			options:      []Option{WithScheme(Natural)},
+			c1:           "!=1.5",
+			c2:           "1.5",
+			intersection: "!=*",
+			union:        "*",
+			overlaps:     false,
+			subset:       false,
+			superset:     false,
+		},
+		{
+			options:      []Option{WithScheme(Maven)},
+			c1:           "<1.5",
+			c2:           ">=1.5",
+			intersection: "!=*",
+			union:        "*",
+			overlaps:     false,
+			subset:       false,
+			superset:     false,
+		},
+		{
+			c1:           "*",
+			c2:           "<1 || >=1",
+			intersection: "*",
+			union:        "*",
+			overlaps:     true,
+			subset:       true,
+			superset:     true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q and %q(%s)", tt.c1, tt.c2, tt.options), func(t *testing.T) {
+			s, err := NewSorter(tt.options...)
+			if !assert.NoError(t, err) {
+				return
+			}
+			c1, err := s.ParseConstraint(tt.c1)
+			if !assert.NoError(t, err) {
+				return
+			}
+			c2, err := s.ParseConstraint(tt.c2)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			intersection := c1.Intersect(c2)
+			assert.Equal(t, tt.intersection, intersection.String())
+			assert.Equal(t, !tt.overlaps, intersection.IsEmpty())
+			assert.Equal(t, tt.union, c1.Union(c2).String())
+			assert.Equal(t, tt.overlaps, c1.Overlaps(c2))
+			assert.Equal(t, tt.subset, c1.IsSubsetOf(c2))
+			assert.Equal(t, tt.superset, c2.IsSubsetOf(c1))
+			assert.Equal(t, tt.subset && tt.superset, c1.Equals(c2))
+
+			// the string of derived constraint can be parsed again
+			reparsed, err := s.ParseConstraint(intersection.String())
+			if assert.NoError(t, err) {
+				assert.True(t, reparsed.Equals(intersection))
+			}
+			reparsed, err = s.ParseConstraint(c1.Union(c2).String())
+			if assert.NoError(t, err) {
+				assert.True(t, reparsed.Equals(c1.Union(c2)))
+			}
+		})
+	}
+}
+
+func TestConstraintComplement(t *testing.T) {
+	s, err := NewSorter()
+	if !assert.NoError(t, err) {
+		return
+	}
+	c, err := s.ParseConstraint(">=1.2 <2 || 3.x")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	complement := c.Complement()
+	assert.Equal(t, "<1.2 || >=2 <3.0 || >=4.0", complement.String())
+	for _, v := range []string{"1.1", "1.2", "1.9.9", "2.0", "3.5", "4.0"} {
+		parsed, err := s.Parse(v)
+		if assert.NoError(t, err) {
+			assert.NotEqual(t, c.Check(parsed), complement.Check(parsed), v)
+		}
+	}
+	assert.True(t, c.Union(complement).Equals(mustParseConstraint(t, s, "*")))
+	assert.True(t, c.Intersect(complement).IsEmpty())
+}
+
+func mustParseConstraint(t *testing.T, s Sorter, expr string) *Constraint {
+	t.Helper()
+	c, err := s.ParseConstraint(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
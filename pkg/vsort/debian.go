@@ -0,0 +1,146 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// debianScheme is version of Debian packages: [epoch:]upstream_version[-debian_revision]
+type debianScheme struct{}
+
+type debianVersion struct {
+	epoch    string
+	upstream string
+	revision string
+}
+
+func (debianScheme) Parse(v string) (interface{}, error) {
+	dv := new(debianVersion)
+	rest := v
+
+	if i := strings.IndexByte(rest, ':'); i >= 0 {
+		epoch := rest[:i]
+		if !isDigits(epoch) {
+			return nil, errors.New("epoch is not numeric")
+		}
+		dv.epoch = normalizeDigits(epoch)
+		rest = rest[i+1:]
+	}
+
+	if i := strings.LastIndexByte(rest, '-'); i >= 0 {
+		dv.revision = rest[i+1:]
+		if dv.revision == "" {
+			return nil, errors.New("revision is empty")
+		}
+		for _, c := range dv.revision {
+			if !isAlnum(c) && !strings.ContainsRune("+.~", c) {
+				return nil, fmt.Errorf("revision contains invalid character %q", c)
+			}
+		}
+		rest = rest[:i]
+	}
+
+	dv.upstream = rest
+	if dv.upstream == "" {
+		return nil, errors.New("upstream version is empty")
+	}
+	if !isDigit(rune(dv.upstream[0])) {
+		return nil, errors.New("upstream version does not start with digit")
+	}
+	for _, c := range dv.upstream {
+		if !isAlnum(c) && !strings.ContainsRune("+-.~", c) {
+			return nil, fmt.Errorf("upstream version contains invalid character %q", c)
+		}
+	}
+
+	return dv, nil
+}
+
+func (debianScheme) Compare(x, y interface{}) int {
+	v1 := x.(*debianVersion)
+	v2 := y.(*debianVersion)
+
+	if r := compareDigits(v1.epoch, v2.epoch); r != 0 {
+		return r
+	}
+	if r := compareDebianString(v1.upstream, v2.upstream); r != 0 {
+		return r
+	}
+
+	return compareDebianString(v1.revision, v2.revision)
+}
+
+func (debianScheme) Format(x interface{}) string {
+	v := x.(*debianVersion)
+	s := v.upstream
+	if v.epoch != "" && v.epoch != "0" {
+		s = v.epoch + ":" + s
+	}
+	if v.revision != "" {
+		s += "-" + v.revision
+	}
+
+	return s
+}
+
+// compareDebianString compares upstream versions or revisions as verrevcmp of dpkg.
+// Non-digit parts and digit parts are compared alternately.
+func compareDebianString(a, b string) int {
+	for a != "" || b != "" {
+		// non-digit part
+		for (a != "" && !isDigit(rune(a[0]))) || (b != "" && !isDigit(rune(b[0]))) {
+			ac := debianOrder(a)
+			bc := debianOrder(b)
+			if ac != bc {
+				return compareInt(ac, bc)
+			}
+			a = a[1:]
+			b = b[1:]
+		}
+
+		// digit part
+		var an, bn string
+		an, a = splitLeadingDigits(a)
+		bn, b = splitLeadingDigits(b)
+		if r := compareDigits(an, bn); r != 0 {
+			return r
+		}
+	}
+
+	return 0
+}
+
+// debianOrder returns the weight of the first character of s in non-digit part.
+// '~' sorts before anything, even the end of part, and letters sort before non-letters.
+func debianOrder(s string) int {
+	if s == "" {
+		return 0
+	}
+
+	c := rune(s[0])
+	switch {
+	case isDigit(c):
+		return 0
+	case isAlpha(c):
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
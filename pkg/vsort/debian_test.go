@@ -0,0 +1,96 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebianCompare(t *testing.T) {
+	cases := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{v1: "1.0", v2: "1.0", expected: 0},
+		{v1: "0:1.0", v2: "1.0", expected: 0},
+		{v1: "1.0", v2: "1.0-0", expected: 0},
+		{v1: "1.9", v2: "1.10", expected: -1},
+		{v1: "123456789012345678901234567890:1.0", v2: "99999999999999999999:2.0", expected: 1},
+		{v1: "1.123456789012345678901234567890", v2: "1.123456789012345678901234567891", expected: -1},
+		{v1: "1:1.0", v2: "2.0", expected: 1},
+		{v1: "1.0~rc1", v2: "1.0", expected: -1},
+		{v1: "1.0~~", v2: "1.0~~a", expected: -1},
+		{v1: "1.0~~a", v2: "1.0~", expected: -1},
+		{v1: "1.0~", v2: "1.0", expected: -1},
+		{v1: "1.0", v2: "1.0a", expected: -1},
+		{v1: "1.0a", v2: "1.0+", expected: -1},
+		{v1: "2.30", v2: "2.30+dfsg", expected: -1},
+		{v1: "2.30~rc1-1", v2: "2.30-1", expected: -1},
+		{v1: "2.30-1", v2: "2.30-1ubuntu3", expected: -1},
+		{v1: "2.30-1ubuntu3", v2: "2.30-1ubuntu10", expected: -1},
+		{v1: "1.0-1", v2: "1.0-1.1", expected: -1},
+		{v1: "1.2-3-4", v2: "1.2-3-3", expected: 1},
+		{v1: "1.0.0001", v2: "1.0.1", expected: 0},
+	}
+
+	s, err := NewSorter(WithScheme(Debian))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q<=>%q", tt.v1, tt.v2), func(t *testing.T) {
+			actual, err := s.Compare(tt.v1, tt.v2)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDebianIsValid(t *testing.T) {
+	cases := []struct {
+		version  string
+		expected bool
+	}{
+		{version: "1.0", expected: true},
+		{version: "1:2.30-1ubuntu3", expected: true},
+		{version: "2.30~rc1-1", expected: true},
+		{version: "2.30+dfsg-2", expected: true},
+		{version: "1.2-3-4", expected: true},
+		{version: "", expected: false},
+		{version: "abc", expected: false},
+		{version: "1.0-", expected: false},
+		{version: ":1.0", expected: false},
+		{version: "a:1.0", expected: false},
+		{version: "1.0_1", expected: false},
+		{version: "1.0-1_2", expected: false},
+	}
+
+	s, err := NewSorter(WithScheme(Debian))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q", tt.version), func(t *testing.T) {
+			assert.Equal(t, tt.expected, s.IsValid(tt.version))
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// WithExtractPattern represents the pattern to find versions in Extract.
+// The pattern should have a capture group named "version".
+type WithExtractPattern string
+
+func (p WithExtractPattern) apply(s *sorter) error {
+	r, err := regexp.Compile(string(p))
+	if err != nil {
+		return err
+	}
+
+	for i, name := range r.SubexpNames() {
+		if i > 0 && name == "version" {
+			s.extractPattern = r
+			s.extractIndex = i
+			return nil
+		}
+	}
+
+	return fmt.Errorf("extract pattern has no capture group named \"version\": %q", string(p))
+}
+
+func (p WithExtractPattern) String() string {
+	return "extract-pattern=" + string(p)
+}
+
+// maxExtractLength is the maximum length of versions without prefix found by Extract without pattern
+const maxExtractLength = 128
+
+// Extract finds a version in text. It returns *ParseError when no version is found.
+// If `WithExtractPattern(...)` is given, the capture group named "version" of the first match is parsed.
+// Otherwise, the leftmost and then longest substring which is a valid version is returned.
+// Substrings are considered only when they start and end at boundaries of words or digits.
+// They start with the prefix pattern if given, and then with a digit or a letter followed by a digit (e.g. "v1").
+// The rest consists of at most maxExtractLength letters, digits and ".-+~_:^".
+func (s *sorter) Extract(text string) (Version, error) {
+	if s.extractPattern != nil {
+		m := s.extractPattern.FindStringSubmatchIndex(text)
+		if m == nil || m[2*s.extractIndex] < 0 {
+			return Version{}, &ParseError{Version: text, Err: fmt.Errorf("version is not found (pattern: %q)", s.extractPattern.String())}
+		}
+		return s.Parse(text[m[2*s.extractIndex]:m[2*s.extractIndex+1]])
+	}
+
+	for start := 0; start < len(text); start++ {
+		if !isExtractBoundary(text, start) {
+			continue
+		}
+
+		coreStart := start
+		if s.prefix != nil {
+			loc := s.prefix.FindStringIndex(text[start:])
+			if loc == nil {
+				continue
+			}
+			coreStart += loc[1]
+		}
+		if !isExtractStart(text, coreStart) {
+			continue
+		}
+
+		limit := coreStart
+		for limit < len(text) && limit-coreStart < maxExtractLength && isVersionChar(text[limit]) {
+			limit++
+		}
+		for end := limit; end > coreStart; end-- {
+			if !isExtractBoundary(text, end) {
+				continue
+			}
+			// errors are not formatted in this loop since most candidates are invalid
+			if v, err := s.parse(text[start:end]); err == nil {
+				return v, nil
+			}
+		}
+	}
+
+	return Version{}, &ParseError{Version: text, Err: errors.New("version is not found")}
+}
+
+// isExtractBoundary reports whether text[:i] and text[i:] are not in the same word or digits
+func isExtractBoundary(text string, i int) bool {
+	if i == 0 || i == len(text) {
+		return true
+	}
+
+	c1, c2 := rune(text[i-1]), rune(text[i])
+	return !isAlnum(c1) || !isAlnum(c2) || isDigit(c1) != isDigit(c2)
+}
+
+// isExtractStart reports whether a version can start at text[i]: a digit or a letter followed by a digit
+func isExtractStart(text string, i int) bool {
+	if i >= len(text) {
+		return false
+	}
+	if isDigit(rune(text[i])) {
+		return true
+	}
+
+	return isAlpha(rune(text[i])) && i+1 < len(text) && isDigit(rune(text[i+1]))
+}
+
+// isVersionChar reports whether c can be a part of versions found by Extract
+func isVersionChar(c byte) bool {
+	return isAlnum(rune(c)) || strings.IndexByte(".-+~_:^", c) >= 0
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	cases := []struct {
+		text     string
+		options  []Option
+		expected string
+	}{
+		{text: "myapp-1.10.2-linux-amd64.tar.gz", options: nil, expected: "1.10.2"},
+		{text: "1.2.3", options: nil, expected: "1.2.3"},
+		{text: "release v2.0 is out", options: []Option{WithPrefix("v")}, expected: "v2.0"},
+		{text: "app1.2", options: nil, expected: "1.2"},
+		{text: "golang.org/x/text@v0.14.0.zip", options: []Option{WithScheme(GoMod)}, expected: "v0.14.0"},
+		{text: "myapp-1.10.2-rc.1 (linux)", options: []Option{WithScheme(SemVer)}, expected: "1.10.2-rc.1"},
+		{text: "myapp-1.10.2 (linux)", options: []Option{WithExtractPattern(`myapp-(?P<version>\S+)`)}, expected: "1.10.2"},
+		{text: "2023-10-01 deployed v1.10.0", options: []Option{WithPrefix("v"), WithExtractPattern(`deployed (?P<version>\S+)`)}, expected: "v1.10.0"},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q(%s)", tt.text, tt.options), func(t *testing.T) {
+			s, err := NewSorter(tt.options...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			v, err := s.Extract(tt.text)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, v.String())
+			}
+		})
+	}
+}
+
+func TestExtractError(t *testing.T) {
+	cases := []struct {
+		text    string
+		options []Option
+	}{
+		{text: "README.md", options: nil},
+		{text: "", options: nil},
+		{text: "myapp-1.10.2", options: []Option{WithExtractPattern(`yourapp-(?P<version>\S+)`)}},
+		{text: "myapp-1.10.x", options: []Option{WithExtractPattern(`myapp-(?P<version>\S+)`)}},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q(%s)", tt.text, tt.options), func(t *testing.T) {
+			s, err := NewSorter(tt.options...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, err = s.Extract(tt.text)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestExtractLongLine(t *testing.T) {
+	cases := []struct {
+		text     string
+		options  []Option
+		expected string
+	}{
+		{text: strings.Repeat("a-", 10000) + "1.2.3", options: nil, expected: "1.2.3"},
+		{text: strings.Repeat("a-", 10000) + "1.2.3", options: []Option{WithScheme(SemVer)}, expected: "1.2.3"},
+		{text: strings.Repeat("a-", 10000) + "v1.2.3", options: []Option{WithPrefix("v")}, expected: "v1.2.3"},
+		{text: strings.Repeat("1-", 2000) + " 1.2.3", options: []Option{WithScheme(SemVer)}, expected: "1.2.3"},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%d(%s)", len(tt.text), tt.options), func(t *testing.T) {
+			s, err := NewSorter(tt.options...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			v, err := s.Extract(tt.text)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, v.String())
+			}
+		})
+	}
+}
+
+func TestWithExtractPatternError(t *testing.T) {
+	for _, pattern := range []string{`myapp-(\S+)`, `myapp-(?P<version>`} {
+		t.Run(pattern, func(t *testing.T) {
+			_, err := NewSorter(WithExtractPattern(pattern))
+			assert.Error(t, err)
+		})
+	}
+}
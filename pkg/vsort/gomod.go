@@ -0,0 +1,69 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"strings"
+)
+
+// goModScheme is version of Go modules. It follows golang.org/x/mod/semver:
+// "v" is required, "vMAJOR" and "vMAJOR.MINOR" are shorthands of "vMAJOR.0.0" and "vMAJOR.MINOR.0",
+// and pseudo-versions are ordered as pre-releases of the next version of their base.
+type goModScheme struct{}
+
+func (goModScheme) Parse(v string) (interface{}, error) {
+	if !strings.HasPrefix(v, "v") {
+		return nil, errors.New("version should start with \"v\"")
+	}
+	rest := v[1:]
+
+	core := rest
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	if n := strings.Count(core, "."); n < 2 {
+		if len(core) != len(rest) {
+			return nil, errors.New("shorthand version cannot have pre-release or build metadata")
+		}
+		rest += strings.Repeat(".0", 2-n)
+	}
+
+	return parseSemVer(rest)
+}
+
+func (goModScheme) Compare(x, y interface{}) int {
+	return semVerScheme{}.Compare(x, y)
+}
+
+func (goModScheme) Format(x interface{}) string {
+	return "v" + semVerScheme{}.Format(x)
+}
+
+func (goModScheme) appendKey(b []byte, x interface{}) []byte {
+	return semVerScheme{}.appendKey(b, x)
+}
+
+func (goModScheme) segments(x interface{}) []string {
+	return semVerScheme{}.segments(x)
+}
+
+func (goModScheme) lowestPreRelease(x interface{}) interface{} {
+	return semVerScheme{}.lowestPreRelease(x)
+}
+
+func (sc goModScheme) fromSegments(segments []string) (interface{}, error) {
+	return sc.Parse("v" + strings.Join(segments, "."))
+}
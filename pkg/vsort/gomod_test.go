@@ -0,0 +1,88 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoModCompare(t *testing.T) {
+	cases := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{v1: "v1", v2: "v1.0.0", expected: 0},
+		{v1: "v1.2", v2: "v1.2.0", expected: 0},
+		{v1: "v2.0.0+incompatible", v2: "v2.0.0", expected: 0},
+		{v1: "v1.2.3", v2: "v1.10.0", expected: -1},
+		{v1: "v2.0.0+incompatible", v2: "v1.9.9", expected: 1},
+		{v1: "v0.0.0-20191109021931-daa7c04131f5", v2: "v0.0.0-20200101000000-0123456789ab", expected: -1},
+		{v1: "v0.0.0-20191109021931-daa7c04131f5", v2: "v0.1.0", expected: -1},
+		{v1: "v1.2.4-0.20191109021931-daa7c04131f5", v2: "v1.2.3", expected: 1},
+		{v1: "v1.2.4-0.20191109021931-daa7c04131f5", v2: "v1.2.4-0.20200101000000-0123456789ab", expected: -1},
+		{v1: "v1.2.4-0.20191109021931-daa7c04131f5", v2: "v1.2.4-pre", expected: -1},
+		{v1: "v1.2.4-0.20191109021931-daa7c04131f5", v2: "v1.2.4", expected: -1},
+		{v1: "v1.2.4-pre.0.20191109021931-daa7c04131f5", v2: "v1.2.4-pre", expected: 1},
+	}
+
+	s, err := NewSorter(WithScheme(GoMod))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q<=>%q", tt.v1, tt.v2), func(t *testing.T) {
+			actual, err := s.Compare(tt.v1, tt.v2)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGoModIsValid(t *testing.T) {
+	cases := []struct {
+		version  string
+		expected bool
+	}{
+		{version: "v1.2.3", expected: true},
+		{version: "v1", expected: true},
+		{version: "v1.2", expected: true},
+		{version: "v2.0.0+incompatible", expected: true},
+		{version: "v0.0.0-20191109021931-daa7c04131f5", expected: true},
+		{version: "v1.2.4-0.20191109021931-daa7c04131f5", expected: true},
+		{version: "1.2.3", expected: false},
+		{version: "v1.2-pre", expected: false},
+		{version: "v1+build", expected: false},
+		{version: "v01.2.3", expected: false},
+		{version: "v1.2.3.4", expected: false},
+		{version: "v", expected: false},
+	}
+
+	s, err := NewSorter(WithScheme(GoMod))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q", tt.version), func(t *testing.T) {
+			assert.Equal(t, tt.expected, s.IsValid(tt.version))
+		})
+	}
+}
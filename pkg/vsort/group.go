@@ -0,0 +1,56 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WithGroupBy represents the named capture group of the prefix pattern used as a grouping key.
+// Sort orders groups lexically in ascending order, and then versions in each group.
+type WithGroupBy string
+
+func (g WithGroupBy) apply(s *sorter) error {
+	if g == "" {
+		return errors.New("group should not be empty")
+	}
+	s.groupBy = string(g)
+
+	return nil
+}
+
+func (g WithGroupBy) String() string {
+	return "group-by=" + string(g)
+}
+
+// resolveGroupBy finds the capture group of groupBy in the prefix pattern
+func (s *sorter) resolveGroupBy() error {
+	if s.groupBy == "" {
+		return nil
+	}
+	if s.prefix == nil {
+		return errors.New("group requires prefix pattern")
+	}
+
+	for i, name := range s.prefix.SubexpNames() {
+		if i > 0 && name == s.groupBy {
+			s.groupIndex = i
+			return nil
+		}
+	}
+
+	return fmt.Errorf("prefix pattern has no capture group named %q", s.groupBy)
+}
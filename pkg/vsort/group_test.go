@@ -0,0 +1,95 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupBySort(t *testing.T) {
+	cases := []struct {
+		versions []string
+		options  []Option
+		expected []string
+	}{
+		{
+			versions: []string{"web/v0.9.1", "api/v1.2.0", "web/v0.10.0", "api/v1.10.0", "api/v0.1.0"},
+			options:  []Option{WithPrefix(`(?P<component>[a-z-]+)/v`), WithGroupBy("component")},
+			expected: []string{"api/v0.1.0", "api/v1.2.0", "api/v1.10.0", "web/v0.9.1", "web/v0.10.0"},
+		},
+		{
+			versions: []string{"web/v0.9.1", "api/v1.2.0", "web/v0.10.0", "api/v1.10.0", "api/v0.1.0"},
+			options:  []Option{WithPrefix(`(?P<component>[a-z-]+)/v`), WithGroupBy("component"), WithOrder(Desc)},
+			expected: []string{"api/v1.10.0", "api/v1.2.0", "api/v0.1.0", "web/v0.10.0", "web/v0.9.1"},
+		},
+		{
+			versions: []string{"web/v0.9.1", "v2.0.0", "api/v1.2.0", "v1.0.0"},
+			options:  []Option{WithPrefix(`((?P<component>[a-z-]+)/)?v`), WithGroupBy("component")},
+			expected: []string{"v1.0.0", "v2.0.0", "api/v1.2.0", "web/v0.9.1"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q(%s)", tt.versions, tt.options), func(t *testing.T) {
+			copied := make([]string, len(tt.versions))
+			copy(copied, tt.versions)
+
+			s, err := NewSorter(tt.options...)
+			if assert.NoError(t, err) {
+				s.Sort(copied)
+				assert.Equal(t, tt.expected, copied)
+			}
+		})
+	}
+}
+
+func TestVersionGroup(t *testing.T) {
+	s, err := NewSorter(WithPrefix(`(?P<component>[a-z-]+)/v`), WithGroupBy("component"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	v, err := s.Parse("api-gateway/v1.2.0")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "api-gateway", v.Group())
+		assert.Equal(t, "api-gateway/v", v.Prefix())
+	}
+
+	// group does not affect comparison of versions
+	r, err := s.Compare("api/v1.2.0", "web/v1.2.0")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, r)
+	}
+}
+
+func TestGroupByError(t *testing.T) {
+	cases := []struct {
+		options []Option
+	}{
+		{options: []Option{WithGroupBy("component")}},
+		{options: []Option{WithPrefix(`(?P<component>[a-z-]+)/v`), WithGroupBy("")}},
+		{options: []Option{WithPrefix(`(?P<component>[a-z-]+)/v`), WithGroupBy("service")}},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%s", tt.options), func(t *testing.T) {
+			_, err := NewSorter(tt.options...)
+			assert.Error(t, err)
+		})
+	}
+}
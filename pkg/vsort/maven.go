@@ -0,0 +1,272 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// mavenScheme is version of Maven artifacts.
+// It follows org.apache.maven.artifact.versioning.ComparableVersion.
+type mavenScheme struct{}
+
+// mavenQualifiers is well-known qualifiers in order. Unknown qualifiers sort after them lexically.
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+// mavenAliases maps alternative spellings of qualifiers
+var mavenAliases = map[string]string{"ga": "", "final": "", "release": "", "cr": "rc"}
+
+// mavenReleaseIndex is comparable qualifier of release version (empty qualifier)
+var mavenReleaseIndex = comparableMavenQualifier("")
+
+// mavenItem is an item of parsed version
+type mavenItem interface {
+	// compare compares the item to other. other may be nil, which means absent item.
+	compare(other mavenItem) int
+	isNull() bool
+	String() string
+}
+
+// mavenInt is a numeric item held as digit string without leading zeros
+type mavenInt string
+
+// mavenString is a (normalized) qualifier item
+type mavenString string
+
+// mavenList is a list of items, separated by '-' or transitions between digits and letters
+type mavenList struct {
+	items []mavenItem
+}
+
+func (mavenScheme) Parse(v string) (interface{}, error) {
+	if v == "" {
+		return nil, errors.New("version is empty")
+	}
+
+	version := strings.ToLower(v)
+	root := new(mavenList)
+	list := root
+	stack := []*mavenList{root}
+	newList := func() {
+		l := new(mavenList)
+		list.items = append(list.items, l)
+		list = l
+		stack = append(stack, l)
+	}
+
+	isDigit := false
+	start := 0
+	for i, c := range version {
+		switch {
+		case c == '.':
+			if i == start {
+				list.items = append(list.items, mavenInt(""))
+			} else {
+				list.items = append(list.items, parseMavenItem(isDigit, version[start:i]))
+			}
+			start = i + 1
+		case c == '-':
+			if i == start {
+				list.items = append(list.items, mavenInt(""))
+			} else {
+				list.items = append(list.items, parseMavenItem(isDigit, version[start:i]))
+			}
+			start = i + 1
+			newList()
+		case '0' <= c && c <= '9':
+			if !isDigit && i > start {
+				list.items = append(list.items, newMavenString(version[start:i], true))
+				start = i
+				newList()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				list.items = append(list.items, parseMavenItem(true, version[start:i]))
+				start = i
+				newList()
+			}
+			isDigit = false
+		}
+	}
+	if len(version) > start {
+		list.items = append(list.items, parseMavenItem(isDigit, version[start:]))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+
+	return root, nil
+}
+
+func (mavenScheme) Compare(x, y interface{}) int {
+	return x.(*mavenList).compare(y.(*mavenList))
+}
+
+func (mavenScheme) Format(x interface{}) string {
+	return x.(*mavenList).String()
+}
+
+func parseMavenItem(isDigit bool, s string) mavenItem {
+	if isDigit {
+		return mavenInt(strings.TrimLeft(s, "0"))
+	}
+
+	return newMavenString(s, false)
+}
+
+func newMavenString(s string, followedByDigit bool) mavenString {
+	if followedByDigit && len(s) == 1 {
+		// a1 = alpha-1, b1 = beta-1, m1 = milestone-1
+		switch s {
+		case "a":
+			s = "alpha"
+		case "b":
+			s = "beta"
+		case "m":
+			s = "milestone"
+		}
+	}
+	if alias, ok := mavenAliases[s]; ok {
+		s = alias
+	}
+
+	return mavenString(s)
+}
+
+func (i mavenInt) compare(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case mavenInt:
+		return compareDigits(string(i), string(o))
+	default:
+		// 1.1 > 1-sp and 1-1 > 1-sp
+		return 1
+	}
+}
+
+func (i mavenInt) isNull() bool {
+	return i == ""
+}
+
+func (i mavenInt) String() string {
+	if i.isNull() {
+		return "0"
+	}
+
+	return string(i)
+}
+
+func (s mavenString) compare(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		// 1-rc < 1, 1-ga == 1, 1-sp > 1
+		return strings.Compare(comparableMavenQualifier(string(s)), mavenReleaseIndex)
+	case mavenString:
+		return strings.Compare(comparableMavenQualifier(string(s)), comparableMavenQualifier(string(o)))
+	default:
+		return -1
+	}
+}
+
+func (s mavenString) isNull() bool {
+	return comparableMavenQualifier(string(s)) == mavenReleaseIndex
+}
+
+func (s mavenString) String() string {
+	return string(s)
+}
+
+// comparableMavenQualifier returns the key of qualifier q which can be compared lexically
+func comparableMavenQualifier(q string) string {
+	for i, known := range mavenQualifiers {
+		if q == known {
+			return strconv.Itoa(i)
+		}
+	}
+
+	return strconv.Itoa(len(mavenQualifiers)) + "-" + q
+}
+
+func (l *mavenList) compare(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		if len(l.items) == 0 {
+			return 0
+		}
+		return l.items[0].compare(nil)
+	case mavenInt:
+		return -1
+	case mavenString:
+		return 1
+	case *mavenList:
+		for i := 0; i < len(l.items) || i < len(o.items); i++ {
+			var r int
+			switch {
+			case i >= len(l.items):
+				r = -o.items[i].compare(nil)
+			case i >= len(o.items):
+				r = l.items[i].compare(nil)
+			default:
+				r = l.items[i].compare(o.items[i])
+			}
+			if r != 0 {
+				return r
+			}
+		}
+		return 0
+	default:
+		return 0
+	}
+}
+
+func (l *mavenList) isNull() bool {
+	return len(l.items) == 0
+}
+
+// String returns the canonical string of l, where sublists are separated by '-'
+func (l *mavenList) String() string {
+	var b strings.Builder
+	for _, item := range l.items {
+		if b.Len() > 0 {
+			if _, ok := item.(*mavenList); ok {
+				b.WriteByte('-')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteString(item.String())
+	}
+
+	return b.String()
+}
+
+// normalize removes trailing null items (0, "", "ga", "final" and "release")
+func (l *mavenList) normalize() {
+	for i := len(l.items) - 1; i >= 0; i-- {
+		if l.items[i].isNull() {
+			l.items = append(l.items[:i], l.items[i+1:]...)
+		} else if _, ok := l.items[i].(*mavenList); !ok {
+			break
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMavenSort(t *testing.T) {
+	// versions in ascending order, taken from ComparableVersionTest of Maven
+	cases := [][]string{
+		{
+			"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2",
+			"1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot",
+			"1-1", "1-2", "1-123",
+		},
+		{
+			"2.0", "2-1", "2.0.a", "2.0.0.a", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1", "2.1.0.1",
+			"2.2", "2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a", "11b", "11c", "11m",
+		},
+	}
+
+	s, err := NewSorter(WithScheme(Maven))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, expected := range cases {
+		t.Run(fmt.Sprintf("%q", expected), func(t *testing.T) {
+			for i := range expected {
+				for j := range expected {
+					actual, err := s.Compare(expected[i], expected[j])
+					if assert.NoError(t, err) {
+						assert.Equal(t, compareInt(i, j), actual, "%q<=>%q", expected[i], expected[j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMavenCompare(t *testing.T) {
+	cases := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{v1: "1", v2: "1.0.0", expected: 0},
+		{v1: "1.0", v2: "1-0", expected: 0},
+		{v1: "1", v2: "1-ga", expected: 0},
+		{v1: "1", v2: "1.final", expected: 0},
+		{v1: "1", v2: "1-RELEASE", expected: 0},
+		{v1: "1cr", v2: "1rc", expected: 0},
+		{v1: "1a1", v2: "1-alpha-1", expected: 0},
+		{v1: "1b2", v2: "1-beta-2", expected: 0},
+		{v1: "1m3", v2: "1-milestone-3", expected: 0},
+		{v1: "1m3", v2: "1milestone3", expected: 0},
+		{v1: "1X", v2: "1x", expected: 0},
+		{v1: "1.0-SNAPSHOT", v2: "1.0", expected: -1},
+		{v1: "1.0", v2: "1.0-sp", expected: -1},
+		{v1: "1.0.0-alpha", v2: "1.0.0-beta", expected: -1},
+		{v1: "1.0.0-rc", v2: "1.0.0-snapshot", expected: -1},
+		{v1: "1.0.1", v2: "1.0.0", expected: 1},
+	}
+
+	s, err := NewSorter(WithScheme(Maven))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q<=>%q", tt.v1, tt.v2), func(t *testing.T) {
+			actual, err := s.Compare(tt.v1, tt.v2)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+// naturalScheme is version sort of GNU coreutils (`sort -V`, `ls -v`).
+// It follows filevercmp of gnulib and accepts any string.
+type naturalScheme struct{}
+
+func (naturalScheme) Parse(v string) (interface{}, error) {
+	return v, nil
+}
+
+func (naturalScheme) Compare(x, y interface{}) int {
+	return fileVerCmp(x.(string), y.(string))
+}
+
+func (naturalScheme) Format(x interface{}) string {
+	return x.(string)
+}
+
+// fileVerCmp compares a and b as filevercmp of gnulib
+func fileVerCmp(a, b string) int {
+	// special case for empty versions
+	if a == "" || b == "" {
+		return compareInt(len(a), len(b))
+	}
+
+	// special cases for leading ".": "." sorts first, then "..", then other names with leading ".", then other names
+	if a[0] == '.' {
+		if b[0] != '.' {
+			return -1
+		}
+		for _, dots := range []string{".", ".."} {
+			if a == dots || b == dots {
+				return compareBool(a != dots, b != dots)
+			}
+		}
+	} else if b[0] == '.' {
+		return 1
+	}
+
+	// cut file suffixes
+	aPrefix := a[:filePrefixLen(a)]
+	bPrefix := b[:filePrefixLen(b)]
+
+	if r := verRevCmp(aPrefix, bPrefix); r != 0 || (aPrefix == a && bPrefix == b) {
+		return r
+	}
+
+	return verRevCmp(a, b)
+}
+
+// filePrefixLen returns the length of s without the longest suffix matched to `(\.[A-Za-z~][A-Za-z0-9~]*)*$`
+func filePrefixLen(s string) int {
+	prefixLen := 0
+	for i := 0; i < len(s); {
+		if s[i] == '.' && i+1 < len(s) && (isAlpha(rune(s[i+1])) || s[i+1] == '~') {
+			for i += 2; i < len(s) && (isAlnum(rune(s[i])) || s[i] == '~'); i++ {
+			}
+			continue
+		}
+		i++
+		prefixLen = i
+	}
+
+	return prefixLen
+}
+
+// verRevCmp is verrevcmp of dpkg slightly modified by gnulib
+func verRevCmp(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(rune(a[i]))) || (j < len(b) && !isDigit(rune(b[j]))) {
+			ac := fileVerOrder(a, i)
+			bc := fileVerOrder(b, j)
+			if ac != bc {
+				return compareInt(ac, bc)
+			}
+			i++
+			j++
+		}
+
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+
+		firstDiff := 0
+		for i < len(a) && j < len(b) && isDigit(rune(a[i])) && isDigit(rune(b[j])) {
+			if firstDiff == 0 {
+				firstDiff = compareInt(int(a[i]), int(b[j]))
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(rune(a[i])) {
+			return 1
+		}
+		if j < len(b) && isDigit(rune(b[j])) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return firstDiff
+		}
+	}
+
+	return 0
+}
+
+// fileVerOrder returns the weight of s[pos] in non-digit part.
+// The end of string sorts before all bytes except '~'.
+func fileVerOrder(s string, pos int) int {
+	if pos == len(s) {
+		return -1
+	}
+
+	c := s[pos]
+	switch {
+	case isDigit(rune(c)):
+		return 0
+	case isAlpha(rune(c)):
+		return int(c)
+	case c == '~':
+		return -2
+	default:
+		return int(c) + 256
+	}
+}
+
+func compareBool(x, y bool) int {
+	switch {
+	case x == y:
+		return 0
+	case x:
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNaturalCompare(t *testing.T) {
+	cases := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{v1: "", v2: "", expected: 0},
+		{v1: "", v2: "a", expected: -1},
+		{v1: ".", v2: "..", expected: -1},
+		{v1: "..", v2: ".a", expected: -1},
+		{v1: ".a", v2: "a", expected: -1},
+		{v1: "a1", v2: "a2", expected: -1},
+		{v1: "a2", v2: "a10", expected: -1},
+		{v1: "a01", v2: "a1", expected: 0},
+		{v1: "1.2", v2: "1.10", expected: -1},
+		{v1: "1.0~rc1", v2: "1.0", expected: -1},
+		{v1: "1.0", v2: "1.0a", expected: -1},
+		{v1: "1.0a", v2: "1.0-", expected: -1},
+		{v1: "foo-1.2.tar.gz", v2: "foo-1.10.tar.gz", expected: -1},
+		{v1: "foo-1.2.tar.gz", v2: "foo-1.2.zip", expected: -1},
+		{v1: "foo-1.2", v2: "foo-1.2.tar.gz", expected: -1},
+		{v1: "myapp-1.10.2-linux-amd64.tar.gz", v2: "myapp-1.9.0-linux-amd64.tar.gz", expected: 1},
+	}
+
+	s, err := NewSorter(WithScheme(Natural))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q<=>%q", tt.v1, tt.v2), func(t *testing.T) {
+			actual, err := s.Compare(tt.v1, tt.v2)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNaturalIsValid(t *testing.T) {
+	s, err := NewSorter(WithScheme(Natural))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, v := range []string{"", "1.0", "foo bar", "~", "..."} {
+		t.Run(fmt.Sprintf("%q", v), func(t *testing.T) {
+			assert.True(t, s.IsValid(v))
+		})
+	}
+}
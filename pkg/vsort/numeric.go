@@ -0,0 +1,101 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"strings"
+)
+
+// numericScheme is dot separated non-negative integers like "1.10.2".
+// Segments are digit strings of any length without leading zeros.
+type numericScheme struct {
+	level   int
+	padding padding
+}
+
+func (n *numericScheme) Parse(v string) (interface{}, error) {
+	nums := strings.Split(v, ".")
+	if n.level > 0 && len(nums) != n.level {
+		return nil, fmt.Errorf("level is not %d", n.level)
+	}
+
+	segments := make([]string, len(nums))
+	for i, num := range nums {
+		if !isDigits(num) {
+			return nil, fmt.Errorf("segment %d is not numeric", i+1)
+		}
+		segments[i] = normalizeDigits(num)
+	}
+
+	return segments, nil
+}
+
+func (n *numericScheme) Compare(x, y interface{}) int {
+	nums1 := x.([]string)
+	nums2 := y.([]string)
+
+	for i := 0; i < len(nums1) || i < len(nums2); i++ {
+		if n.padding == PadLess && (i >= len(nums1) || i >= len(nums2)) {
+			return compareInt(len(nums1), len(nums2))
+		}
+
+		// missing segments are treated as zero
+		var num1, num2 string
+		if i < len(nums1) {
+			num1 = nums1[i]
+		}
+		if i < len(nums2) {
+			num2 = nums2[i]
+		}
+
+		if r := compareDigits(num1, num2); r != 0 {
+			return r
+		}
+	}
+
+	return 0
+}
+
+func (n *numericScheme) Format(x interface{}) string {
+	return strings.Join(x.([]string), ".")
+}
+
+func (n *numericScheme) appendKey(b []byte, x interface{}) []byte {
+	nums := x.([]string)
+	if n.padding != PadLess {
+		// trailing zeros are insignificant unless shorter is smaller
+		for len(nums) > 0 && nums[len(nums)-1] == "0" {
+			nums = nums[:len(nums)-1]
+		}
+	}
+	for _, num := range nums {
+		b = appendDigitsKey(b, num)
+	}
+
+	return b
+}
+
+func (n *numericScheme) segments(x interface{}) []string {
+	return strings.Split(n.Format(x), ".")
+}
+
+func (n *numericScheme) fromSegments(segments []string) (interface{}, error) {
+	for len(segments) < n.level {
+		segments = append(segments, "0")
+	}
+
+	return n.Parse(strings.Join(segments, "."))
+}
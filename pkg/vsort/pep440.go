@@ -0,0 +1,227 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// pep440Scheme is version of Python packages defined by PEP 440
+type pep440Scheme struct{}
+
+// pep440Pattern is the pattern of valid versions including permitted alternative spellings
+var pep440Pattern = regexp.MustCompile(`(?i)^v?` +
+	`(?:(?P<epoch>[0-9]+)!)?` +
+	`(?P<release>[0-9]+(?:\.[0-9]+)*)` +
+	`(?:[-_.]?(?P<pre_l>a|b|c|rc|alpha|beta|pre|preview)[-_.]?(?P<pre_n>[0-9]+)?)?` +
+	`(?:-(?P<post_n1>[0-9]+)|[-_.]?(?P<post_l>post|rev|r)[-_.]?(?P<post_n2>[0-9]+)?)?` +
+	`(?:[-_.]?(?P<dev_l>dev)[-_.]?(?P<dev_n>[0-9]+)?)?` +
+	`(?:\+(?P<local>[a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+// pep440PreLabels maps spellings of pre-release to normalized one
+var pep440PreLabels = map[string]string{
+	"a": "a", "alpha": "a",
+	"b": "b", "beta": "b",
+	"rc": "rc", "c": "rc", "pre": "rc", "preview": "rc",
+}
+
+// pep440PreOrder is the order of normalized pre-release labels
+var pep440PreOrder = map[string]int{"a": 0, "b": 1, "rc": 2}
+
+// pep440Version is a normalized PEP 440 version.
+// All numbers are held as digit strings without leading zeros.
+type pep440Version struct {
+	epoch   string
+	release []string
+	preL    string
+	preN    string
+	post    *string
+	dev     *string
+	local   []string
+}
+
+func (pep440Scheme) Parse(v string) (interface{}, error) {
+	m := pep440Pattern.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return nil, errors.New("invalid PEP 440 version")
+	}
+	group := func(name string) string {
+		for i, n := range pep440Pattern.SubexpNames() {
+			if n == name {
+				return m[i]
+			}
+		}
+		return ""
+	}
+
+	pv := &pep440Version{epoch: normalizeDigits(group("epoch"))}
+
+	for _, r := range strings.Split(group("release"), ".") {
+		pv.release = append(pv.release, normalizeDigits(r))
+	}
+
+	if l := group("pre_l"); l != "" {
+		pv.preL = pep440PreLabels[strings.ToLower(l)]
+		pv.preN = normalizeDigits(group("pre_n"))
+	}
+
+	if n := group("post_n1"); n != "" {
+		post := normalizeDigits(n)
+		pv.post = &post
+	} else if group("post_l") != "" {
+		post := normalizeDigits(group("post_n2"))
+		pv.post = &post
+	}
+
+	if group("dev_l") != "" {
+		dev := normalizeDigits(group("dev_n"))
+		pv.dev = &dev
+	}
+
+	if l := group("local"); l != "" {
+		pv.local = strings.FieldsFunc(strings.ToLower(l), func(c rune) bool {
+			return c == '-' || c == '_' || c == '.'
+		})
+	}
+
+	return pv, nil
+}
+
+func (pep440Scheme) Compare(x, y interface{}) int {
+	v1 := x.(*pep440Version)
+	v2 := y.(*pep440Version)
+
+	if r := compareDigits(v1.epoch, v2.epoch); r != 0 {
+		return r
+	}
+
+	// trailing zeros of release are insignificant
+	for i := 0; i < len(v1.release) || i < len(v2.release); i++ {
+		var r1, r2 string
+		if i < len(v1.release) {
+			r1 = v1.release[i]
+		}
+		if i < len(v2.release) {
+			r2 = v2.release[i]
+		}
+		if r := compareDigits(r1, r2); r != 0 {
+			return r
+		}
+	}
+
+	if r := compareInt(v1.preRank(), v2.preRank()); r != 0 {
+		return r
+	}
+	if v1.preL != "" {
+		if r := compareInt(pep440PreOrder[v1.preL], pep440PreOrder[v2.preL]); r != 0 {
+			return r
+		}
+		if r := compareDigits(v1.preN, v2.preN); r != 0 {
+			return r
+		}
+	}
+
+	// no post-release sorts before any post-release
+	if r := compareOptionalDigits(v1.post, v2.post, -1); r != 0 {
+		return r
+	}
+
+	// no dev-release sorts after any dev-release
+	if r := compareOptionalDigits(v1.dev, v2.dev, 1); r != 0 {
+		return r
+	}
+
+	return comparePEP440Local(v1.local, v2.local)
+}
+
+func (pep440Scheme) Format(x interface{}) string {
+	return x.(*pep440Version).String()
+}
+
+// preRank returns -1 for dev-releases of final release, 0 for pre-releases and 1 for others
+func (v *pep440Version) preRank() int {
+	switch {
+	case v.preL == "" && v.post == nil && v.dev != nil:
+		return -1
+	case v.preL == "":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// String returns the normalized form of v
+func (v *pep440Version) String() string {
+	var b strings.Builder
+	if v.epoch != "0" {
+		b.WriteString(v.epoch + "!")
+	}
+	b.WriteString(strings.Join(v.release, "."))
+	if v.preL != "" {
+		b.WriteString(v.preL + v.preN)
+	}
+	if v.post != nil {
+		b.WriteString(".post" + *v.post)
+	}
+	if v.dev != nil {
+		b.WriteString(".dev" + *v.dev)
+	}
+	if len(v.local) > 0 {
+		b.WriteString("+" + strings.Join(v.local, "."))
+	}
+
+	return b.String()
+}
+
+// compareOptionalDigits compares optional numbers. absent is returned when only x is absent.
+func compareOptionalDigits(x, y *string, absent int) int {
+	switch {
+	case x == nil && y == nil:
+		return 0
+	case x == nil:
+		return absent
+	case y == nil:
+		return -absent
+	default:
+		return compareDigits(*x, *y)
+	}
+}
+
+// comparePEP440Local compares local version labels.
+// Numeric segments sort after alphanumeric ones, and no local label sorts before any.
+func comparePEP440Local(l1, l2 []string) int {
+	for i := 0; i < len(l1) && i < len(l2); i++ {
+		num1 := isDigits(l1[i])
+		num2 := isDigits(l2[i])
+		var r int
+		switch {
+		case num1 && num2:
+			r = compareDigits(l1[i], l2[i])
+		case num1:
+			r = 1
+		case num2:
+			r = -1
+		default:
+			r = strings.Compare(l1[i], l2[i])
+		}
+		if r != 0 {
+			return r
+		}
+	}
+
+	return compareInt(len(l1), len(l2))
+}
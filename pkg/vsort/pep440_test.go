@@ -0,0 +1,132 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPEP440Compare(t *testing.T) {
+	cases := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{v1: "1.0", v2: "1.0.0", expected: 0},
+		{v1: "1.0alpha1", v2: "1.0a1", expected: 0},
+		{v1: "1.0-1", v2: "1.0.post1", expected: 0},
+		{v1: "1.0rev", v2: "1.0.post0", expected: 0},
+		{v1: "1.0+ubuntu-1", v2: "1.0+ubuntu.1", expected: 0},
+		{v1: "v1.0", v2: "1.0", expected: 0},
+		{v1: "1!1.0", v2: "2.0", expected: 1},
+		{v1: "1.0.dev1", v2: "1.0a1", expected: -1},
+		{v1: "1.0c1", v2: "1.0b2", expected: 1},
+		{v1: "1.0a1.post1", v2: "1.0a2", expected: -1},
+		{v1: "1.0.post1.dev1", v2: "1.0.post1", expected: -1},
+		{v1: "1.0.post1", v2: "1.0.1", expected: -1},
+		{v1: "1.0+abc", v2: "1.0+5", expected: -1},
+		{v1: "1.0+abc.5", v2: "1.0+abc", expected: 1},
+	}
+
+	s, err := NewSorter(WithScheme(PEP440))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q<=>%q", tt.v1, tt.v2), func(t *testing.T) {
+			actual, err := s.Compare(tt.v1, tt.v2)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPEP440Sort(t *testing.T) {
+	// the example of PEP 440
+	expected := []string{
+		"1.0.dev456", "1.0a1", "1.0a2.dev456", "1.0a12.dev456", "1.0a12",
+		"1.0b1.dev456", "1.0b2", "1.0b2.post345.dev456", "1.0b2.post345",
+		"1.0rc1.dev456", "1.0rc1", "1.0", "1.0+abc.5", "1.0+abc.7", "1.0+5",
+		"1.0.post456.dev34", "1.0.post456", "1.0.15", "1.1.dev1",
+	}
+	versions := make([]string, len(expected))
+	for i, v := range expected {
+		versions[len(versions)-i-1] = v
+	}
+
+	s, err := NewSorter(WithScheme(PEP440))
+	if assert.NoError(t, err) {
+		s.Sort(versions)
+		assert.Equal(t, expected, versions)
+	}
+}
+
+func TestPEP440IsValid(t *testing.T) {
+	cases := []struct {
+		version  string
+		expected bool
+	}{
+		{version: "1.0", expected: true},
+		{version: "1!2.0", expected: true},
+		{version: "1.0.0RC1", expected: true},
+		{version: "1.0-preview.2", expected: true},
+		{version: "2023.10.1.post1.dev3+ubuntu1", expected: true},
+		{version: "1.0+", expected: false},
+		{version: "1.0.x", expected: false},
+		{version: "1.0gamma1", expected: false},
+		{version: "a1.0", expected: false},
+		{version: "1.0+abc_", expected: false},
+	}
+
+	s, err := NewSorter(WithScheme(PEP440))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q", tt.version), func(t *testing.T) {
+			assert.Equal(t, tt.expected, s.IsValid(tt.version))
+		})
+	}
+}
+
+func TestPEP440Normalize(t *testing.T) {
+	cases := []struct {
+		version  string
+		expected string
+	}{
+		{version: "1.0alpha1", expected: "1.0a1"},
+		{version: "01.02", expected: "1.2"},
+		{version: "0!1.0-c.2", expected: "1.0rc2"},
+		{version: "1.0-1", expected: "1.0.post1"},
+		{version: "1.0_r", expected: "1.0.post0"},
+		{version: "1.0-DEV", expected: "1.0.dev0"},
+		{version: "v1!1.0+Ubuntu-1", expected: "1!1.0+ubuntu.1"},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q", tt.version), func(t *testing.T) {
+			v, err := pep440Scheme{}.Parse(tt.version)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, v.(*pep440Version).String())
+			}
+		})
+	}
+}
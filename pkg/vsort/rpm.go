@@ -0,0 +1,211 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// rpmScheme is version of RPM packages: [epoch:]version[-release]
+type rpmScheme struct{}
+
+type rpmVersion struct {
+	epoch   string
+	version string
+	release string
+}
+
+func (rpmScheme) Parse(v string) (interface{}, error) {
+	rv := new(rpmVersion)
+	rest := v
+
+	if i := strings.IndexByte(rest, ':'); i >= 0 {
+		epoch := rest[:i]
+		if !isDigits(epoch) {
+			return nil, errors.New("epoch is not numeric")
+		}
+		rv.epoch = normalizeDigits(epoch)
+		rest = rest[i+1:]
+	}
+
+	if i := strings.LastIndexByte(rest, '-'); i >= 0 {
+		rv.release = rest[i+1:]
+		if rv.release == "" {
+			return nil, errors.New("release is empty")
+		}
+		if err := validateRPMString(rv.release); err != nil {
+			return nil, fmt.Errorf("release %s", err)
+		}
+		rest = rest[:i]
+	}
+
+	rv.version = rest
+	if rv.version == "" {
+		return nil, errors.New("version is empty")
+	}
+	if err := validateRPMString(rv.version); err != nil {
+		return nil, fmt.Errorf("version %s", err)
+	}
+
+	return rv, nil
+}
+
+func validateRPMString(s string) error {
+	for _, c := range s {
+		if !isAlnum(c) && !strings.ContainsRune("._+~^", c) {
+			return fmt.Errorf("contains invalid character %q", c)
+		}
+	}
+
+	return nil
+}
+
+func (rpmScheme) Compare(x, y interface{}) int {
+	v1 := x.(*rpmVersion)
+	v2 := y.(*rpmVersion)
+
+	if r := compareDigits(v1.epoch, v2.epoch); r != 0 {
+		return r
+	}
+	if r := compareRPMString(v1.version, v2.version); r != 0 {
+		return r
+	}
+
+	// a missing release is older than any release
+	switch {
+	case v1.release == "" && v2.release == "":
+		return 0
+	case v1.release == "":
+		return -1
+	case v2.release == "":
+		return 1
+	}
+
+	return compareRPMString(v1.release, v2.release)
+}
+
+func (rpmScheme) Format(x interface{}) string {
+	v := x.(*rpmVersion)
+	s := v.version
+	if v.epoch != "" && v.epoch != "0" {
+		s = v.epoch + ":" + s
+	}
+	if v.release != "" {
+		s += "-" + v.release
+	}
+
+	return s
+}
+
+// compareRPMString compares versions or releases as rpmvercmp of rpm.
+func compareRPMString(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	for a != "" || b != "" {
+		a = strings.TrimLeftFunc(a, isRPMSeparator)
+		b = strings.TrimLeftFunc(b, isRPMSeparator)
+
+		// '~' sorts before everything, even the end of string
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a = a[1:]
+			b = b[1:]
+			continue
+		}
+
+		// '^' sorts after the end of string, but before everything else
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			if a == "" {
+				return -1
+			}
+			if b == "" {
+				return 1
+			}
+			if !strings.HasPrefix(a, "^") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "^") {
+				return -1
+			}
+			a = a[1:]
+			b = b[1:]
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		var seg1, seg2 string
+		isNum := isDigit(rune(a[0]))
+		if isNum {
+			seg1, a = splitLeadingDigits(a)
+			seg2, b = splitLeadingDigits(b)
+		} else {
+			seg1, a = splitLeadingAlphas(a)
+			seg2, b = splitLeadingAlphas(b)
+		}
+
+		// numeric segments are always newer than alpha segments
+		if seg2 == "" {
+			if isNum {
+				return 1
+			}
+			return -1
+		}
+
+		var r int
+		if isNum {
+			r = compareDigits(seg1, seg2)
+		} else {
+			r = strings.Compare(seg1, seg2)
+		}
+		if r != 0 {
+			return r
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	default:
+		return 1
+	}
+}
+
+func isRPMSeparator(c rune) bool {
+	return !isAlnum(c) && c != '~' && c != '^'
+}
+
+// splitLeadingAlphas splits s into its leading letters and the rest
+func splitLeadingAlphas(s string) (string, string) {
+	i := 0
+	for i < len(s) && isAlpha(rune(s[i])) {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPMCompare(t *testing.T) {
+	cases := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{v1: "1.0", v2: "1.0", expected: 0},
+		{v1: "1.0", v2: "2.0", expected: -1},
+		{v1: "2.0.1", v2: "2.0", expected: 1},
+		{v1: "2.0.1a", v2: "2.0.1", expected: 1},
+		{v1: "5.5p1", v2: "5.5p2", expected: -1},
+		{v1: "5.5p10", v2: "5.5p2", expected: 1},
+		{v1: "10xyz", v2: "10.1xyz", expected: -1},
+		{v1: "xyz10", v2: "xyz10.1", expected: -1},
+		{v1: "2a", v2: "2.0", expected: -1},
+		{v1: "2_0", v2: "2.0", expected: 0},
+		{v1: "1.0010", v2: "1.9", expected: 1},
+		{v1: "123456789012345678901234567890:1.0", v2: "99999999999999999999:2.0", expected: 1},
+		{v1: "1.0~rc1", v2: "1.0", expected: -1},
+		{v1: "1.0~rc1", v2: "1.0~rc2", expected: -1},
+		{v1: "1.0~rc1~git123", v2: "1.0~rc1", expected: -1},
+		{v1: "1.0^", v2: "1.0", expected: 1},
+		{v1: "1.0^git1", v2: "1.0", expected: 1},
+		{v1: "1.0^git1", v2: "1.0.1", expected: -1},
+		{v1: "1.0^git1", v2: "1.0^git2", expected: -1},
+		{v1: "1.0~rc1^git1", v2: "1.0~rc1", expected: 1},
+		{v1: "1.0^git1~pre", v2: "1.0^git1", expected: -1},
+		{v1: "1:1.0-1", v2: "2.0-1", expected: 1},
+		{v1: "0:1.0-1", v2: "1.0-1", expected: 0},
+		{v1: "1.0-1", v2: "1.0-2", expected: -1},
+		{v1: "1.0-1.el8", v2: "1.0-1.el9", expected: -1},
+		{v1: "1.0", v2: "1.0-1", expected: -1},
+	}
+
+	s, err := NewSorter(WithScheme(RPM))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q<=>%q", tt.v1, tt.v2), func(t *testing.T) {
+			actual, err := s.Compare(tt.v1, tt.v2)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRPMIsValid(t *testing.T) {
+	cases := []struct {
+		version  string
+		expected bool
+	}{
+		{version: "1.0", expected: true},
+		{version: "2:1.0.2k-21.el7_9", expected: true},
+		{version: "1.0~rc1^git1", expected: true},
+		{version: "", expected: false},
+		{version: "1.0-", expected: false},
+		{version: "x:1.0", expected: false},
+		{version: "1.0 1", expected: false},
+		{version: "1.0-1-2", expected: false},
+	}
+
+	s, err := NewSorter(WithScheme(RPM))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q", tt.version), func(t *testing.T) {
+			assert.Equal(t, tt.expected, s.IsValid(tt.version))
+		})
+	}
+}
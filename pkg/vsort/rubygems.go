@@ -0,0 +1,115 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// rubyGemsScheme is version of Ruby gems. It follows Gem::Version.
+type rubyGemsScheme struct{}
+
+// rubyGemsPattern is the pattern of Gem::Version::ANCHORED_VERSION_PATTERN
+var rubyGemsPattern = regexp.MustCompile(`^\s*[0-9]+(?:\.[0-9a-zA-Z]+)*(?:-[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?\s*$`)
+
+var rubyGemsSegmentPattern = regexp.MustCompile(`[0-9]+|[a-zA-Z]+`)
+
+// rubyGemsVersion is a version with its canonical segments
+type rubyGemsVersion struct {
+	version  string
+	segments []rubyGemsSegment
+}
+
+// rubyGemsSegment is a segment of version. Numeric segments are held as digit strings without leading zeros.
+type rubyGemsSegment struct {
+	isNum bool
+	str   string
+}
+
+func (rubyGemsScheme) Parse(v string) (interface{}, error) {
+	if !rubyGemsPattern.MatchString(v) {
+		return nil, errors.New("malformed version number string")
+	}
+
+	// "-" means prerelease as well as letters
+	version := strings.Replace(strings.TrimSpace(v), "-", ".pre.", -1)
+
+	var numeric, str []rubyGemsSegment
+	for _, s := range rubyGemsSegmentPattern.FindAllString(version, -1) {
+		seg := rubyGemsSegment{isNum: isDigits(s), str: s}
+		if seg.isNum {
+			seg.str = strings.TrimLeft(s, "0")
+		}
+		if !seg.isNum || len(str) > 0 {
+			str = append(str, seg)
+		} else {
+			numeric = append(numeric, seg)
+		}
+	}
+
+	// canonical segments: trailing zeros of numeric part and string part are insignificant
+	return &rubyGemsVersion{
+		version:  strings.TrimSpace(v),
+		segments: append(trimRubyGemsZeros(numeric), trimRubyGemsZeros(str)...),
+	}, nil
+}
+
+func trimRubyGemsZeros(segments []rubyGemsSegment) []rubyGemsSegment {
+	i := len(segments)
+	for i > 0 && segments[i-1].isNum && segments[i-1].str == "" {
+		i--
+	}
+
+	return segments[:i]
+}
+
+func (rubyGemsScheme) Compare(x, y interface{}) int {
+	s1 := x.(*rubyGemsVersion).segments
+	s2 := y.(*rubyGemsVersion).segments
+
+	zero := rubyGemsSegment{isNum: true}
+	for i := 0; i < len(s1) || i < len(s2); i++ {
+		seg1, seg2 := zero, zero
+		if i < len(s1) {
+			seg1 = s1[i]
+		}
+		if i < len(s2) {
+			seg2 = s2[i]
+		}
+
+		switch {
+		case seg1.isNum && seg2.isNum:
+			if r := compareDigits(seg1.str, seg2.str); r != 0 {
+				return r
+			}
+		case seg1.isNum:
+			return 1
+		case seg2.isNum:
+			return -1
+		default:
+			if r := strings.Compare(seg1.str, seg2.str); r != 0 {
+				return r
+			}
+		}
+	}
+
+	return 0
+}
+
+func (rubyGemsScheme) Format(x interface{}) string {
+	return x.(*rubyGemsVersion).version
+}
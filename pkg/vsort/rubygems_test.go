@@ -0,0 +1,86 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRubyGemsCompare(t *testing.T) {
+	cases := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{v1: "1", v2: "1.0.0", expected: 0},
+		{v1: "1.0", v2: "1.0.0", expected: 0},
+		{v1: "0.beta.1", v2: "0.0.beta.1", expected: 0},
+		{v1: "1.0.0.pre2", v2: "1.0.0", expected: -1},
+		{v1: "1.8.2.a", v2: "1.8.2", expected: -1},
+		{v1: "1.2.b1", v2: "1.2", expected: -1},
+		{v1: "1.0.a", v2: "1.0.b1", expected: -1},
+		{v1: "1.0.a10", v2: "1.0.a9", expected: 1},
+		{v1: "5.a", v2: "5.0.0.rc2", expected: -1},
+		{v1: "1.0.0-1", v2: "1.0.0", expected: -1},
+		{v1: "1.9.3-p550", v2: "1.9.3.pre.p.550", expected: 0},
+		{v1: "1.2.0.10", v2: "1.2.0.9", expected: 1},
+		{v1: "1.0.rc1", v2: "1.0.1", expected: -1},
+	}
+
+	s, err := NewSorter(WithScheme(RubyGems))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q<=>%q", tt.v1, tt.v2), func(t *testing.T) {
+			actual, err := s.Compare(tt.v1, tt.v2)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRubyGemsIsValid(t *testing.T) {
+	cases := []struct {
+		version  string
+		expected bool
+	}{
+		{version: "1.0", expected: true},
+		{version: "1.0.0.pre2", expected: true},
+		{version: "1.9.3-p550", expected: true},
+		{version: " 1.0 ", expected: true},
+		{version: "", expected: false},
+		{version: "junk", expected: false},
+		{version: "1..2", expected: false},
+		{version: "1.0\n2.0", expected: false},
+		{version: "1.2 3.4", expected: false},
+	}
+
+	s, err := NewSorter(WithScheme(RubyGems))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q", tt.version), func(t *testing.T) {
+			assert.Equal(t, tt.expected, s.IsValid(tt.version))
+		})
+	}
+}
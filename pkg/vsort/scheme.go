@@ -0,0 +1,244 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Names of built-in version schemes which can be passed to Sort via `WithScheme(...)`
+const (
+	// Numeric is the default scheme: dot separated non-negative integers
+	Numeric = "numeric"
+	// SemVer is Semantic Versioning 2.0.0 (https://semver.org/)
+	SemVer = "semver"
+	// Debian is version of Debian packages compared as dpkg does
+	Debian = "debian"
+	// RPM is version of RPM packages compared as rpmvercmp does
+	RPM = "rpm"
+	// PEP440 is version of Python packages (https://www.python.org/dev/peps/pep-0440/)
+	PEP440 = "pep440"
+	// Maven is version of Maven artifacts compared as ComparableVersion does
+	Maven = "maven"
+	// RubyGems is version of Ruby gems compared as Gem::Version does
+	RubyGems = "rubygems"
+	// CalVer is calendar versioning (https://calver.org/), which requires `WithCalVerFormat(...)`
+	CalVer = "calver"
+	// GoMod is version of Go modules including pseudo-versions
+	GoMod = "gomod"
+	// Natural is version sort of GNU coreutils (`sort -V`), which accepts any string
+	Natural = "natural"
+)
+
+// Scheme defines syntax and precedence of a version format.
+// Prefix and suffix are already stripped from strings passed to Scheme.
+type Scheme interface {
+	// Parse parses v into a scheme specific value.
+	// It returns an error when v is not a valid version of the scheme.
+	// The error should describe the reason without quoting v, which is quoted by Sorter.Parse.
+	Parse(v string) (interface{}, error)
+	// Compare returns an integer comparing two values returned by Parse.
+	// The result will be 0 if x==y, -1 if x < y, and +1 if x > y.
+	Compare(x, y interface{}) int
+	// Format returns the canonical string of a value returned by Parse.
+	Format(x interface{}) string
+}
+
+// schemeFactory makes a scheme configured by options of sorter
+type schemeFactory func(s *sorter) (Scheme, error)
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = map[string]schemeFactory{
+		Numeric: func(s *sorter) (Scheme, error) {
+			if s.calverFormat != "" {
+				return nil, errors.New("calver format is supported only by calver scheme")
+			}
+			return &numericScheme{level: s.level, padding: s.padding}, nil
+		},
+		CalVer: func(s *sorter) (Scheme, error) {
+			if s.level > 0 {
+				return nil, errors.New("level is supported only by numeric scheme")
+			}
+			if s.padding != PadZero {
+				return nil, errors.New("padding is supported only by numeric scheme")
+			}
+			return newCalVerScheme(s.calverFormat)
+		},
+		SemVer:   staticScheme(semVerScheme{}),
+		Debian:   staticScheme(debianScheme{}),
+		RPM:      staticScheme(rpmScheme{}),
+		PEP440:   staticScheme(pep440Scheme{}),
+		Maven:    staticScheme(mavenScheme{}),
+		RubyGems: staticScheme(rubyGemsScheme{}),
+		GoMod:    staticScheme(goModScheme{}),
+		Natural:  staticScheme(naturalScheme{}),
+	}
+)
+
+// staticScheme returns the factory of scheme which has no configuration
+func staticScheme(sc Scheme) schemeFactory {
+	return func(s *sorter) (Scheme, error) {
+		if s.level > 0 {
+			return nil, errors.New("level is supported only by numeric scheme")
+		}
+		if s.calverFormat != "" {
+			return nil, errors.New("calver format is supported only by calver scheme")
+		}
+		if s.padding != PadZero {
+			return nil, errors.New("padding is supported only by numeric scheme")
+		}
+		return sc, nil
+	}
+}
+
+// RegisterScheme makes a scheme available by the provided name.
+// If RegisterScheme is called twice with the same name or if sc is nil, it panics.
+func RegisterScheme(name string, sc Scheme) {
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+
+	if sc == nil {
+		panic("vsort: RegisterScheme scheme is nil")
+	}
+	if _, dup := schemes[name]; dup {
+		panic("vsort: RegisterScheme called twice for scheme " + name)
+	}
+	schemes[name] = staticScheme(sc)
+}
+
+// Schemes returns a sorted list of the names of the registered schemes
+func Schemes() []string {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// WithScheme represents version scheme of Sort by the registered name
+type WithScheme string
+
+func (sc WithScheme) apply(s *sorter) error {
+	s.schemeName = string(sc)
+
+	return nil
+}
+
+func (sc WithScheme) String() string {
+	return "scheme=" + string(sc)
+}
+
+// newScheme returns the scheme selected by options of s
+func newScheme(s *sorter) (Scheme, error) {
+	schemesMu.RLock()
+	factory, ok := schemes[s.schemeName]
+	schemesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown scheme: %q", s.schemeName)
+	}
+
+	return factory(s)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !isDigit(c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDigit(c rune) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isAlpha(c rune) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isAlnum(c rune) bool {
+	return isDigit(c) || isAlpha(c)
+}
+
+func compareInt(x, y int) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// splitLeadingDigits splits s into its leading digits and the rest
+func splitLeadingDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(rune(s[i])) {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+// compareDigits compares two digit strings numerically.
+// Empty string is treated as zero.
+func compareDigits(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if r := compareInt(len(a), len(b)); r != 0 {
+		return r
+	}
+
+	return strings.Compare(a, b)
+}
+
+// appendDigitsKey appends the key of digit string s to b.
+// Keys are ordered bytewise as compareDigits, and each key can be followed by other keys.
+func appendDigitsKey(b []byte, s string) []byte {
+	s = strings.TrimLeft(s, "0")
+	if n := len(s); n < 0xff {
+		b = append(b, byte(n))
+	} else {
+		b = append(b, 0xff, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+
+	return append(b, s...)
+}
+
+// normalizeDigits strips leading zeros of digit string s. Empty string is treated as zero.
+func normalizeDigits(s string) string {
+	s = strings.TrimLeft(s, "0")
+	if s == "" {
+		return "0"
+	}
+
+	return s
+}
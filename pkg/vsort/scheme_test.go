@@ -0,0 +1,114 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// buildNumberScheme is a scheme of versions like "b123" for testing
+type buildNumberScheme struct{}
+
+func (buildNumberScheme) Parse(v string) (interface{}, error) {
+	if !strings.HasPrefix(v, "b") {
+		return nil, errors.New("should start with \"b\"")
+	}
+	return strconv.Atoi(v[1:])
+}
+
+func (buildNumberScheme) Compare(x, y interface{}) int {
+	return compareInt(x.(int), y.(int))
+}
+
+func (buildNumberScheme) Format(x interface{}) string {
+	return "b" + strconv.Itoa(x.(int))
+}
+
+// unregisterScheme removes the scheme registered by RegisterScheme for testing
+func unregisterScheme(name string) {
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+
+	delete(schemes, name)
+}
+
+func TestRegisterScheme(t *testing.T) {
+	RegisterScheme("test-build-number", buildNumberScheme{})
+	defer unregisterScheme("test-build-number")
+
+	assert.Contains(t, Schemes(), "test-build-number")
+	assert.Panics(t, func() { RegisterScheme("test-build-number", buildNumberScheme{}) })
+	assert.Panics(t, func() { RegisterScheme("test-nil", nil) })
+
+	s, err := NewSorter(WithScheme("test-build-number"), WithPrefix("release-"))
+	if assert.NoError(t, err) {
+		versions := []string{"release-b10", "release-b9", "release-b100"}
+		s.Sort(versions)
+		assert.Equal(t, []string{"release-b9", "release-b10", "release-b100"}, versions)
+		assert.False(t, s.IsValid("release-10"))
+	}
+
+	_, err = NewSorter(WithScheme("test-build-number"), WithLevel(2))
+	assert.Error(t, err)
+}
+
+func TestSchemes(t *testing.T) {
+	names := Schemes()
+	for _, name := range []string{Numeric, SemVer, Debian, RPM, PEP440, Maven, RubyGems, CalVer, GoMod, Natural} {
+		assert.Contains(t, names, name)
+	}
+	assert.True(t, sort.StringsAreSorted(names))
+}
+
+func TestSchemeFormat(t *testing.T) {
+	cases := []struct {
+		options  []Option
+		version  string
+		expected string
+	}{
+		{version: "1.02.3", expected: "1.2.3"},
+		{options: []Option{WithScheme(SemVer)}, version: "1.2.3-rc.1+build.5", expected: "1.2.3-rc.1+build.5"},
+		{options: []Option{WithScheme(Debian)}, version: "0:2.30-1ubuntu3", expected: "2.30-1ubuntu3"},
+		{options: []Option{WithScheme(RPM)}, version: "1:1.0.2k-21.el7_9", expected: "1:1.0.2k-21.el7_9"},
+		{options: []Option{WithScheme(PEP440)}, version: "1.0-Alpha-1", expected: "1.0a1"},
+		{options: []Option{WithScheme(Maven)}, version: "1.0.0-GA-1", expected: "1-1"},
+		{options: []Option{WithScheme(Maven)}, version: "1.0-alpha1", expected: "1-alpha-1"},
+		{options: []Option{WithScheme(RubyGems)}, version: " 1.0.0.pre2 ", expected: "1.0.0.pre2"},
+		{options: []Option{WithScheme(CalVer), WithCalVerFormat("YYYY.0M.MICRO")}, version: "2023.01.4", expected: "2023.01.4"},
+		{options: []Option{WithScheme(GoMod)}, version: "v1.2", expected: "v1.2.0"},
+		{options: []Option{WithScheme(Natural)}, version: "foo-1.2", expected: "foo-1.2"},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q(%s)", tt.version, tt.options), func(t *testing.T) {
+			s, err := NewSorter(tt.options...)
+			if !assert.NoError(t, err) {
+				return
+			}
+			sc := s.(*sorter).scheme
+			x, err := sc.Parse(tt.version)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, sc.Format(x))
+			}
+		})
+	}
+}
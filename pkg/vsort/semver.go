@@ -0,0 +1,228 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// semVerScheme is Semantic Versioning 2.0.0
+type semVerScheme struct{}
+
+// semVerVersion is a parsed semantic version. Numbers are digit strings of any length.
+type semVerVersion struct {
+	major, minor, patch string
+	pre                 []semVerIdentifier
+	build               []string
+}
+
+// semVerIdentifier is a dot separated identifier of pre-release
+type semVerIdentifier struct {
+	isNum bool
+	str   string
+}
+
+func (semVerScheme) Parse(v string) (interface{}, error) {
+	return parseSemVer(v)
+}
+
+// parseSemVer parses v as semantic version
+func parseSemVer(v string) (*semVerVersion, error) {
+	sv := new(semVerVersion)
+	rest := v
+
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		build, err := splitSemVerIdentifiers(rest[i+1:], "build metadata")
+		if err != nil {
+			return nil, err
+		}
+		sv.build = build
+		rest = rest[:i]
+	}
+
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		pre, err := splitSemVerIdentifiers(rest[i+1:], "pre-release")
+		if err != nil {
+			return nil, err
+		}
+		sv.pre = make([]semVerIdentifier, len(pre))
+		for j, p := range pre {
+			if !isDigits(p) {
+				sv.pre[j] = semVerIdentifier{str: p}
+				continue
+			}
+			if err := validateSemVerNumber(p); err != nil {
+				return nil, fmt.Errorf("pre-release identifier %d %s", j+1, err)
+			}
+			sv.pre[j] = semVerIdentifier{isNum: true, str: p}
+		}
+		rest = rest[:i]
+	}
+
+	core := strings.Split(rest, ".")
+	if len(core) != 3 {
+		return nil, errors.New("version core should be MAJOR.MINOR.PATCH")
+	}
+	for i, c := range core {
+		if !isDigits(c) {
+			return nil, fmt.Errorf("segment %d is not numeric", i+1)
+		}
+		if err := validateSemVerNumber(c); err != nil {
+			return nil, fmt.Errorf("segment %d %s", i+1, err)
+		}
+	}
+	sv.major, sv.minor, sv.patch = core[0], core[1], core[2]
+
+	return sv, nil
+}
+
+func (semVerScheme) Compare(x, y interface{}) int {
+	v1 := x.(*semVerVersion)
+	v2 := y.(*semVerVersion)
+
+	if r := compareDigits(v1.major, v2.major); r != 0 {
+		return r
+	}
+	if r := compareDigits(v1.minor, v2.minor); r != 0 {
+		return r
+	}
+	if r := compareDigits(v1.patch, v2.patch); r != 0 {
+		return r
+	}
+
+	// a version without pre-release has higher precedence
+	switch {
+	case len(v1.pre) == 0 && len(v2.pre) == 0:
+		return 0
+	case len(v1.pre) == 0:
+		return 1
+	case len(v2.pre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v1.pre) && i < len(v2.pre); i++ {
+		if r := v1.pre[i].compare(v2.pre[i]); r != 0 {
+			return r
+		}
+	}
+
+	return compareInt(len(v1.pre), len(v2.pre))
+}
+
+func (semVerScheme) Format(x interface{}) string {
+	v := x.(*semVerVersion)
+	s := v.major + "." + v.minor + "." + v.patch
+	if len(v.pre) > 0 {
+		pre := make([]string, len(v.pre))
+		for i, id := range v.pre {
+			pre[i] = id.String()
+		}
+		s += "-" + strings.Join(pre, ".")
+	}
+	if len(v.build) > 0 {
+		s += "+" + strings.Join(v.build, ".")
+	}
+
+	return s
+}
+
+func (semVerScheme) appendKey(b []byte, x interface{}) []byte {
+	v := x.(*semVerVersion)
+	b = appendDigitsKey(b, v.major)
+	b = appendDigitsKey(b, v.minor)
+	b = appendDigitsKey(b, v.patch)
+	if len(v.pre) == 0 {
+		// a version without pre-release has higher precedence
+		return append(b, 2)
+	}
+
+	b = append(b, 1)
+	for _, id := range v.pre {
+		if id.isNum {
+			b = append(b, 1)
+			b = appendDigitsKey(b, id.str)
+		} else {
+			b = append(b, 2)
+			b = append(b, id.str...)
+			b = append(b, 0)
+		}
+	}
+
+	return b
+}
+
+func (semVerScheme) segments(x interface{}) []string {
+	v := x.(*semVerVersion)
+	return []string{v.major, v.minor, v.patch}
+}
+
+func (semVerScheme) lowestPreRelease(x interface{}) interface{} {
+	v := x.(*semVerVersion)
+
+	return &semVerVersion{major: v.major, minor: v.minor, patch: v.patch, pre: []semVerIdentifier{{isNum: true, str: "0"}}}
+}
+
+func (sc semVerScheme) fromSegments(segments []string) (interface{}, error) {
+	for len(segments) < 3 {
+		segments = append(segments, "0")
+	}
+
+	return sc.Parse(strings.Join(segments, "."))
+}
+
+func (id semVerIdentifier) compare(other semVerIdentifier) int {
+	switch {
+	case id.isNum && other.isNum:
+		return compareDigits(id.str, other.str)
+	case id.isNum:
+		// numeric identifiers have lower precedence than alphanumeric ones
+		return -1
+	case other.isNum:
+		return 1
+	default:
+		return strings.Compare(id.str, other.str)
+	}
+}
+
+func (id semVerIdentifier) String() string {
+	return id.str
+}
+
+func splitSemVerIdentifiers(s, kind string) ([]string, error) {
+	ids := strings.Split(s, ".")
+	for i, id := range ids {
+		if id == "" {
+			return nil, fmt.Errorf("%s identifier %d is empty", kind, i+1)
+		}
+		for _, c := range id {
+			if !isAlnum(c) && c != '-' {
+				return nil, fmt.Errorf("%s identifier %d contains invalid character %q", kind, i+1, c)
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+// validateSemVerNumber returns an error if digit string s has leading zeros
+func validateSemVerNumber(s string) error {
+	if len(s) > 1 && s[0] == '0' {
+		return errors.New("has leading zeros")
+	}
+
+	return nil
+}
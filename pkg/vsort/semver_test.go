@@ -0,0 +1,98 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSemVerCompare(t *testing.T) {
+	cases := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{v1: "1.0.0", v2: "1.0.0", expected: 0},
+		{v1: "1.0.0", v2: "2.0.0", expected: -1},
+		{v1: "2.0.0", v2: "2.1.0", expected: -1},
+		{v1: "2.1.1", v2: "2.1.0", expected: 1},
+		{v1: "1.0.0-alpha", v2: "1.0.0", expected: -1},
+		{v1: "1.0.0-alpha", v2: "1.0.0-alpha.1", expected: -1},
+		{v1: "1.0.0-alpha.1", v2: "1.0.0-alpha.beta", expected: -1},
+		{v1: "1.0.0-alpha.beta", v2: "1.0.0-beta", expected: -1},
+		{v1: "1.0.0-beta", v2: "1.0.0-beta.2", expected: -1},
+		{v1: "1.0.0-beta.2", v2: "1.0.0-beta.11", expected: -1},
+		{v1: "1.0.0-beta.11", v2: "1.0.0-rc.1", expected: -1},
+		{v1: "1.0.0-rc.1", v2: "1.0.0", expected: -1},
+		{v1: "1.0.0+build.1", v2: "1.0.0+build.2", expected: 0},
+		{v1: "1.0.0-rc.1+build.1", v2: "1.0.0-rc.1", expected: 0},
+		{v1: "1.0.0-x-y", v2: "1.0.0-x", expected: 1},
+		{v1: "1.0.123456789012345678901234567890", v2: "1.0.123456789012345678901234567891", expected: -1},
+		{v1: "1.0.0-rc.123456789012345678901234567890", v2: "1.0.0-rc.99999999999999999999", expected: 1},
+		{v1: "18446744073709551616.0.0", v2: "18446744073709551615.0.0", expected: 1},
+	}
+
+	s, err := NewSorter(WithScheme(SemVer))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q<=>%q", tt.v1, tt.v2), func(t *testing.T) {
+			actual, err := s.Compare(tt.v1, tt.v2)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSemVerIsValid(t *testing.T) {
+	cases := []struct {
+		version  string
+		expected bool
+	}{
+		{version: "0.1.0", expected: true},
+		{version: "1.2.3-rc.1", expected: true},
+		{version: "1.2.3+build.5", expected: true},
+		{version: "1.2.3-0.3.7+exp.sha.5114f85", expected: true},
+		{version: "1.2.3--", expected: true},
+		{version: "1.2", expected: false},
+		{version: "1.2.3.4", expected: false},
+		{version: "01.2.3", expected: false},
+		{version: "1.2.3-01", expected: false},
+		{version: "1.2.123456789012345678901234567890", expected: true},
+		{version: "1.2.0123456789012345678901234567890", expected: false},
+		{version: "1.2.3-", expected: false},
+		{version: "1.2.3-rc..1", expected: false},
+		{version: "1.2.3+", expected: false},
+		{version: "1.2.3-rc_1", expected: false},
+		{version: "v1.2.3", expected: false},
+	}
+
+	s, err := NewSorter(WithScheme(SemVer))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q", tt.version), func(t *testing.T) {
+			assert.Equal(t, tt.expected, s.IsValid(tt.version))
+		})
+	}
+}
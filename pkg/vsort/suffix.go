@@ -0,0 +1,131 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// suffixKey is a named capture group of the suffix pattern used as a secondary key
+type suffixKey struct {
+	name    string
+	numeric bool
+	index   int
+}
+
+// WithSuffixKey makes a named capture group of the suffix pattern a secondary key of comparison.
+// It is formatted as "NAME", "NAME:numeric" or "NAME:lexical", and compared numerically by default.
+// Keys are compared in the given order when versions are equivalent.
+type WithSuffixKey string
+
+func (k WithSuffixKey) apply(s *sorter) error {
+	name, kind := string(k), "numeric"
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		name, kind = name[:i], name[i+1:]
+	}
+	if name == "" {
+		return errors.New("suffix key should not be empty")
+	}
+
+	switch kind {
+	case "numeric":
+		s.suffixKeys = append(s.suffixKeys, suffixKey{name: name, numeric: true})
+	case "lexical":
+		s.suffixKeys = append(s.suffixKeys, suffixKey{name: name, numeric: false})
+	default:
+		return fmt.Errorf("kind of suffix key should be numeric or lexical: %q", string(k))
+	}
+
+	return nil
+}
+
+func (k WithSuffixKey) String() string {
+	return "suffix-key=" + string(k)
+}
+
+// resolveSuffixKeys finds capture groups of suffix keys in the suffix pattern
+func (s *sorter) resolveSuffixKeys() error {
+	if len(s.suffixKeys) == 0 {
+		return nil
+	}
+	if s.suffix == nil {
+		return errors.New("suffix key requires suffix pattern")
+	}
+
+	names := s.suffix.SubexpNames()
+	for i := range s.suffixKeys {
+		k := &s.suffixKeys[i]
+		for j, name := range names {
+			if j > 0 && name == k.name {
+				k.index = j
+				break
+			}
+		}
+		if k.index == 0 {
+			return fmt.Errorf("suffix pattern has no capture group named %q", k.name)
+		}
+	}
+
+	return nil
+}
+
+// suffixKeyValues returns values of suffix keys in submatch of the suffix pattern against core
+func (s *sorter) suffixKeyValues(core string, submatch []int) ([]string, error) {
+	if len(s.suffixKeys) == 0 {
+		return nil, nil
+	}
+
+	values := make([]string, len(s.suffixKeys))
+	for i, k := range s.suffixKeys {
+		start, end := submatch[2*k.index], submatch[2*k.index+1]
+		if start < 0 {
+			// unmatched optional group is treated as empty
+			continue
+		}
+		values[i] = core[start:end]
+		if k.numeric && !isDigits(values[i]) {
+			return nil, fmt.Errorf("suffix key %q is not numeric: %q", k.name, values[i])
+		}
+	}
+
+	return values, nil
+}
+
+// compareSuffixKeys compares values of suffix keys.
+// An empty value is smaller than any other values.
+func (s *sorter) compareSuffixKeys(values1, values2 []string) int {
+	for i, k := range s.suffixKeys {
+		if i >= len(values1) || i >= len(values2) {
+			break
+		}
+
+		var r int
+		switch {
+		case values1[i] == "" || values2[i] == "":
+			r = compareBool(values1[i] != "", values2[i] != "")
+		case k.numeric:
+			r = compareDigits(values1[i], values2[i])
+		default:
+			r = strings.Compare(values1[i], values2[i])
+		}
+		if r != 0 {
+			return r
+		}
+	}
+
+	return 0
+}
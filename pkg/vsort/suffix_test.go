@@ -0,0 +1,136 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuffixKeyCompare(t *testing.T) {
+	cases := []struct {
+		options  []Option
+		v1       string
+		v2       string
+		expected int
+	}{
+		{
+			options:  []Option{WithSuffix(`-(?P<build>\d+)`)},
+			v1:       "1.2.0-10",
+			v2:       "1.2.0-3",
+			expected: 0,
+		},
+		{
+			options:  []Option{WithSuffix(`-(?P<build>\d+)`), WithSuffixKey("build")},
+			v1:       "1.2.0-10",
+			v2:       "1.2.0-3",
+			expected: 1,
+		},
+		{
+			options:  []Option{WithSuffix(`-(?P<build>\d+)`), WithSuffixKey("build")},
+			v1:       "1.1.0-10",
+			v2:       "1.2.0-3",
+			expected: -1,
+		},
+		{
+			options:  []Option{WithSuffix(`-(?P<build>\d+)`), WithSuffixKey("build:lexical")},
+			v1:       "1.2.0-10",
+			v2:       "1.2.0-3",
+			expected: -1,
+		},
+		{
+			options:  []Option{WithSuffix(`-r(?P<rev>\d+)`), WithSuffixKey("rev")},
+			v1:       "1.21.3-r4",
+			v2:       "1.21.3-r12",
+			expected: -1,
+		},
+		{
+			options:  []Option{WithSuffix(`-(?P<kind>[a-z]+)(?P<num>\d+)`), WithSuffixKey("kind:lexical"), WithSuffixKey("num")},
+			v1:       "2.3.1-build57",
+			v2:       "2.3.1-build123",
+			expected: -1,
+		},
+		{
+			options:  []Option{WithSuffix(`-(?P<kind>[a-z]+)(?P<num>\d+)`), WithSuffixKey("kind:lexical"), WithSuffixKey("num")},
+			v1:       "2.3.1-build57",
+			v2:       "2.3.1-alpha123",
+			expected: 1,
+		},
+		{
+			options:  []Option{WithSuffix(`(-(?P<build>\d+))?`), WithSuffixKey("build")},
+			v1:       "1.2.0",
+			v2:       "1.2.0-0",
+			expected: -1,
+		},
+		{
+			options:  []Option{WithSuffix(`(-(?P<build>\d+))?`), WithSuffixKey("build")},
+			v1:       "1.2.0-007",
+			v2:       "1.2.0-7",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q<=>%q(%s)", tt.v1, tt.v2, tt.options), func(t *testing.T) {
+			s, err := NewSorter(tt.options...)
+			if assert.NoError(t, err) {
+				actual, err := s.Compare(tt.v1, tt.v2)
+				if assert.NoError(t, err) {
+					assert.Equal(t, tt.expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestSuffixKeySort(t *testing.T) {
+	s, err := NewSorter(WithSuffix(`-(?P<kind>[a-z]+)(?P<num>\d+)`), WithSuffixKey("kind:lexical"), WithSuffixKey("num"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	versions := []string{"2.3.1-build57", "2.3.0-build99", "2.3.1-build123", "2.3.1-alpha7", "2.3.1-build9"}
+	s.Sort(versions)
+	assert.Equal(t, []string{"2.3.0-build99", "2.3.1-alpha7", "2.3.1-build9", "2.3.1-build57", "2.3.1-build123"}, versions)
+}
+
+func TestSuffixKeyError(t *testing.T) {
+	cases := []struct {
+		options []Option
+	}{
+		{options: []Option{WithSuffixKey("build")}},
+		{options: []Option{WithSuffix(`-(?P<build>\d+)`), WithSuffixKey("")}},
+		{options: []Option{WithSuffix(`-(?P<build>\d+)`), WithSuffixKey("build:unknown")}},
+		{options: []Option{WithSuffix(`-(?P<build>\d+)`), WithSuffixKey("rev")}},
+		{options: []Option{WithSuffix(`-(\d+)`), WithSuffixKey("1")}},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%s", tt.options), func(t *testing.T) {
+			_, err := NewSorter(tt.options...)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("not numeric", func(t *testing.T) {
+		s, err := NewSorter(WithSuffix(`-(?P<build>\w+)`), WithSuffixKey("build"))
+		if assert.NoError(t, err) {
+			assert.True(t, s.IsValid("1.2.0-10"))
+			assert.False(t, s.IsValid("1.2.0-b10"))
+		}
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Version is a version string parsed by Sorter
+type Version struct {
+	sorter     *sorter
+	original   string
+	prefix     string
+	group      string
+	core       string
+	suffix     string
+	suffixKeys []string
+	value      interface{}
+}
+
+// segmenter is implemented by schemes whose versions consist of numeric segments
+type segmenter interface {
+	segments(x interface{}) []string
+}
+
+// Original returns the whole text of v
+func (v Version) Original() string {
+	return v.original
+}
+
+// Prefix returns the text of v matched to the prefix pattern
+func (v Version) Prefix() string {
+	return v.prefix
+}
+
+// Group returns the text of v matched to the capture group of prefix pattern given by `WithGroupBy(...)`
+func (v Version) Group() string {
+	return v.group
+}
+
+// Suffix returns the text of v matched to the suffix pattern
+func (v Version) Suffix() string {
+	return v.suffix
+}
+
+// Core returns the text of v without prefix and suffix
+func (v Version) Core() string {
+	return v.core
+}
+
+// isEmpty reports whether v holds no version (zero value or returned by Sorter.EmptyVersion)
+func (v Version) isEmpty() bool {
+	return v.sorter == nil || v.value == nil
+}
+
+// Canonical returns the canonical form of the core of v formatted by the scheme
+func (v Version) Canonical() string {
+	if v.isEmpty() {
+		return ""
+	}
+
+	return v.sorter.scheme.Format(v.value)
+}
+
+// Segments returns numeric segments of v as decimal strings (e.g. "1", "2" and "3" of "1.2.3").
+// It returns nil when the scheme does not define numeric segments.
+func (v Version) Segments() []string {
+	if v.isEmpty() {
+		return nil
+	}
+	sg, ok := v.sorter.scheme.(segmenter)
+	if !ok {
+		return nil
+	}
+
+	return sg.segments(v.value)
+}
+
+// Parsed returns the scheme specific value of v returned by Scheme.Parse
+func (v Version) Parsed() interface{} {
+	return v.value
+}
+
+// Compare returns an integer comparing v and other, which should be parsed by the same Sorter.
+// The result will be 0 if v==other, -1 if v < other, and +1 if v > other.
+// Zero value of Version (and Version returned by Sorter.EmptyVersion) is less than any parsed version.
+// Suffix keys are compared when the versions are equivalent in the scheme.
+//
+// Compare never reports padding errors: missing segments are treated as zero
+// even if the Sorter is configured with `WithPadding(PadError)`. Use Sorter.Compare to detect them.
+func (v Version) Compare(other Version) int {
+	switch {
+	case v.isEmpty() && other.isEmpty():
+		return 0
+	case v.isEmpty():
+		return -1
+	case other.isEmpty():
+		return 1
+	}
+
+	if r := v.sorter.scheme.Compare(v.value, other.value); r != 0 {
+		return r
+	}
+
+	return v.sorter.compareSuffixKeys(v.suffixKeys, other.suffixKeys)
+}
+
+// String returns the whole text of v
+func (v Version) String() string {
+	return v.original
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.original), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The text is parsed by the Sorter which v is bound to by Sorter.Parse or Sorter.EmptyVersion,
+// or the default Sorter if v is zero value.
+func (v *Version) UnmarshalText(text []byte) error {
+	s := v.sorter
+	if s == nil {
+		d, err := NewSorter()
+		if err != nil {
+			return err
+		}
+		s = d.(*sorter)
+	}
+
+	parsed, err := s.Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (v Version) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.original)
+}
+
+// UnmarshalJSON implements json.Unmarshaler in the same way as UnmarshalText
+func (v *Version) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	return v.UnmarshalText([]byte(text))
+}
+
+// Scan implements sql.Scanner in the same way as UnmarshalText
+func (v *Version) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case string:
+		return v.UnmarshalText([]byte(src))
+	case []byte:
+		return v.UnmarshalText(src)
+	default:
+		return fmt.Errorf("cannot scan %T into Version", src)
+	}
+}
+
+// Value implements driver.Valuer
+func (v Version) Value() (driver.Value, error) {
+	return v.original, nil
+}
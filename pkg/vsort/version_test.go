@@ -0,0 +1,251 @@
+// Copyright (C) 2020 Akira Tanimura (@autopp)
+//
+// Licensed under the Apache License, Version 2.0 (the “License”);
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an “AS IS” BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vsort
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSorterParse(t *testing.T) {
+	cases := []struct {
+		options   []Option
+		version   string
+		prefix    string
+		core      string
+		suffix    string
+		segments  []string
+		canonical string
+	}{
+		{
+			version:   "0.1.0",
+			core:      "0.1.0",
+			segments:  []string{"0", "1", "0"},
+			canonical: "0.1.0",
+		},
+		{
+			options:   []Option{WithPrefix("[a-z]+-"), WithSuffix(`-\d+`)},
+			version:   "release-1.02-3",
+			prefix:    "release-",
+			core:      "1.02",
+			suffix:    "-3",
+			segments:  []string{"1", "2"},
+			canonical: "1.2",
+		},
+		{
+			options:   []Option{WithScheme(SemVer), WithPrefix("v")},
+			version:   "v1.2.3-rc.1",
+			prefix:    "v",
+			core:      "1.2.3-rc.1",
+			segments:  []string{"1", "2", "3"},
+			canonical: "1.2.3-rc.1",
+		},
+		{
+			options:   []Option{WithScheme(PEP440)},
+			version:   "1.0alpha1",
+			core:      "1.0alpha1",
+			canonical: "1.0a1",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q(%s)", tt.version, tt.options), func(t *testing.T) {
+			s, err := NewSorter(tt.options...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			v, err := s.Parse(tt.version)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.version, v.Original())
+				assert.Equal(t, tt.version, v.String())
+				assert.Equal(t, tt.prefix, v.Prefix())
+				assert.Equal(t, tt.core, v.Core())
+				assert.Equal(t, tt.suffix, v.Suffix())
+				assert.Equal(t, tt.segments, v.Segments())
+				assert.Equal(t, tt.canonical, v.Canonical())
+			}
+		})
+	}
+}
+
+func TestSorterParseError(t *testing.T) {
+	s, err := NewSorter(WithPrefix("v"), WithSuffix(`-\d+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	cases := []struct {
+		version string
+		reason  string
+	}{
+		{version: "1.0-1", reason: `prefix is not match (prefix: "^v")`},
+		{version: "v1.0", reason: `suffix is not match (suffix: "-\\d+$")`},
+		{version: "v1.x-1", reason: "segment 2 is not numeric"},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q", tt.version), func(t *testing.T) {
+			_, err := s.Parse(tt.version)
+			var pe *ParseError
+			if assert.True(t, errors.As(err, &pe)) {
+				assert.Equal(t, tt.version, pe.Version)
+				assert.EqualError(t, pe.Err, tt.reason)
+				assert.Equal(t, pe.Err, errors.Unwrap(err))
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	s, err := NewSorter(WithScheme(SemVer))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	v1, err := s.Parse("1.0.0-rc.1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	v2, err := s.Parse("1.0.0")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, -1, v1.Compare(v2))
+	assert.Equal(t, 1, v2.Compare(v1))
+	assert.Equal(t, 0, v1.Compare(v1))
+
+	// zero value is less than any parsed version
+	var zero Version
+	assert.Equal(t, -1, zero.Compare(v1))
+	assert.Equal(t, 1, v1.Compare(zero))
+	assert.Equal(t, 0, zero.Compare(Version{}))
+}
+
+func TestVersionMarshaling(t *testing.T) {
+	type config struct {
+		Version Version `json:"version"`
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		var c config
+		if assert.NoError(t, json.Unmarshal([]byte(`{"version":"1.10.0"}`), &c)) {
+			assert.Equal(t, []string{"1", "10", "0"}, c.Version.Segments())
+
+			b, err := json.Marshal(c)
+			if assert.NoError(t, err) {
+				assert.Equal(t, `{"version":"1.10.0"}`, string(b))
+			}
+		}
+
+		assert.Error(t, json.Unmarshal([]byte(`{"version":"1.x"}`), &c))
+		assert.Error(t, json.Unmarshal([]byte(`{"version":1}`), &c))
+	})
+
+	t.Run("TextWithSorter", func(t *testing.T) {
+		s, err := NewSorter(WithScheme(SemVer))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		v, err := s.Parse("0.0.0")
+		if !assert.NoError(t, err) {
+			return
+		}
+		if assert.NoError(t, v.UnmarshalText([]byte("1.0.0-rc.1"))) {
+			text, err := v.MarshalText()
+			if assert.NoError(t, err) {
+				assert.Equal(t, "1.0.0-rc.1", string(text))
+			}
+		}
+		assert.Error(t, v.UnmarshalText([]byte("1.0")))
+	})
+
+	t.Run("JSONWithEmptyVersion", func(t *testing.T) {
+		cases := []struct {
+			options []Option
+			version string
+		}{
+			{options: []Option{WithScheme(SemVer)}, version: "1.2.3-rc.1"},
+			{options: []Option{WithPrefix("v")}, version: "v1.2.3"},
+			{options: []Option{WithScheme(Debian)}, version: "1:2.30-1ubuntu3"},
+		}
+
+		for _, tt := range cases {
+			t.Run(tt.version, func(t *testing.T) {
+				s, err := NewSorter(tt.options...)
+				if !assert.NoError(t, err) {
+					return
+				}
+				v, err := s.Parse(tt.version)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				b, err := json.Marshal(config{Version: v})
+				if !assert.NoError(t, err) {
+					return
+				}
+				c := config{Version: s.EmptyVersion()}
+				if assert.NoError(t, json.Unmarshal(b, &c)) {
+					assert.Equal(t, tt.version, c.Version.String())
+					assert.Equal(t, 0, c.Version.Compare(v))
+				}
+			})
+		}
+	})
+
+	t.Run("EmptyVersion", func(t *testing.T) {
+		s, err := NewSorter(WithScheme(SemVer))
+		if !assert.NoError(t, err) {
+			return
+		}
+		v, err := s.Parse("1.0.0")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		empty := s.EmptyVersion()
+		assert.Equal(t, "", empty.String())
+		assert.Equal(t, "", empty.Canonical())
+		assert.Nil(t, empty.Segments())
+		assert.Equal(t, -1, empty.Compare(v))
+		assert.Equal(t, 0, empty.Compare(Version{}))
+		assert.Error(t, empty.Scan("1.0"))
+		if assert.NoError(t, empty.Scan("1.0.0-rc.1")) {
+			assert.Equal(t, -1, empty.Compare(v))
+		}
+	})
+
+	t.Run("SQL", func(t *testing.T) {
+		var v Version
+		if assert.NoError(t, v.Scan([]byte("1.2.3"))) {
+			value, err := v.Value()
+			if assert.NoError(t, err) {
+				assert.Equal(t, driver.Value("1.2.3"), value)
+			}
+		}
+		assert.NoError(t, v.Scan("2.0"))
+		assert.Equal(t, "2.0", v.String())
+		assert.Error(t, v.Scan(nil))
+		assert.Error(t, v.Scan(int64(1)))
+	})
+}
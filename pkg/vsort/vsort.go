@@ -19,7 +19,6 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 )
 
@@ -27,7 +26,14 @@ import (
 type Sorter interface {
 	Compare(v1, v2 string) (int, error)
 	Sort(versions []string)
+	SortE(versions []string) error
+	Partition(versions []string) ([]string, []Reject)
+	Less(x, y Version) bool
 	IsValid(v string) bool
+	Parse(v string) (Version, error)
+	EmptyVersion() Version
+	Extract(text string) (Version, error)
+	ParseConstraint(expr string) (*Constraint, error)
 }
 
 type order int
@@ -39,11 +45,48 @@ const (
 	Desc
 )
 
+type padding int
+
+const (
+	// PadZero treats missing segments as zero (e.g. "1.2" == "1.2.0"). It is the default.
+	PadZero padding = iota
+	// PadLess treats a version with less segments as smaller (e.g. "1.2" < "1.2.0")
+	PadLess
+	// PadError makes Compare and SortE return an error for versions with different segment counts, and Partition rejects them.
+	// Sort, Less and Version.Compare cannot report errors, and treat missing segments as zero.
+	PadError
+)
+
+type tieBreak int
+
+const (
+	// NoTieBreak leaves order of equivalent versions unspecified. It is the default.
+	NoTieBreak tieBreak = iota
+	// TieBreakInput keeps input order of equivalent versions
+	TieBreakInput
+	// TieBreakLexical orders equivalent versions by the whole strings lexically
+	TieBreakLexical
+	// TieBreakSuffix orders equivalent versions by the stripped suffix, and then prefix lexically
+	TieBreakSuffix
+)
+
 type sorter struct {
-	order  order
-	prefix *regexp.Regexp
-	suffix *regexp.Regexp
-	level  int
+	order      order
+	prefix     *regexp.Regexp
+	suffix     *regexp.Regexp
+	level      int
+	padding    padding
+	stable     bool
+	tieBreak   tieBreak
+	suffixKeys []suffixKey
+	groupBy    string
+	groupIndex int
+	// pattern and index of capture group for Extract
+	extractPattern *regexp.Regexp
+	extractIndex   int
+	schemeName     string
+	calverFormat   string
+	scheme         Scheme
 }
 
 // Option is Functional optional pattern object for Sort
@@ -123,105 +166,410 @@ func (l WithLevel) String() string {
 	return fmt.Sprintf("level=%d", int(l))
 }
 
+// WithPadding represents how versions with different segment counts are compared.
+// It is supported only by numeric scheme.
+// With PadError, Sort silently treats missing segments as zero; use SortE or Partition to detect them.
+type WithPadding padding
+
+func (p WithPadding) apply(s *sorter) error {
+	pv := padding(p)
+	if pv != PadZero && pv != PadLess && pv != PadError {
+		return errors.New("padding should be one of PadZero, PadLess or PadError")
+	}
+	s.padding = pv
+
+	return nil
+}
+
+func (p WithPadding) String() string {
+	switch padding(p) {
+	case PadZero:
+		return "padding=zero"
+	case PadLess:
+		return "padding=less"
+	case PadError:
+		return "padding=error"
+	default:
+		return "padding=unknown"
+	}
+}
+
+// WithStable represents whether Sort keeps input order of equivalent versions
+type WithStable bool
+
+func (st WithStable) apply(s *sorter) error {
+	s.stable = bool(st)
+
+	return nil
+}
+
+func (st WithStable) String() string {
+	return fmt.Sprintf("stable=%t", bool(st))
+}
+
+// WithTieBreak represents how Sort orders equivalent versions.
+// Versions still equivalent after tie-breaking keep input order when `WithStable(true)` is given.
+type WithTieBreak tieBreak
+
+func (tb WithTieBreak) apply(s *sorter) error {
+	tbv := tieBreak(tb)
+	if tbv != NoTieBreak && tbv != TieBreakInput && tbv != TieBreakLexical && tbv != TieBreakSuffix {
+		return errors.New("tie-break should be one of NoTieBreak, TieBreakInput, TieBreakLexical or TieBreakSuffix")
+	}
+	s.tieBreak = tbv
+
+	return nil
+}
+
+func (tb WithTieBreak) String() string {
+	switch tieBreak(tb) {
+	case NoTieBreak:
+		return "tie-break=none"
+	case TieBreakInput:
+		return "tie-break=input"
+	case TieBreakLexical:
+		return "tie-break=lexical"
+	case TieBreakSuffix:
+		return "tie-break=suffix"
+	default:
+		return "tie-break=unknown"
+	}
+}
+
 // NewSorter returns Sorter initialized by given options
 func NewSorter(options ...Option) (Sorter, error) {
-	defaults := []Option{WithLevel(-1)}
+	defaults := []Option{WithLevel(-1), WithScheme(Numeric)}
 	s := new(sorter)
 	for _, o := range append(defaults, options...) {
 		if err := o.apply(s); err != nil {
 			return nil, err
 		}
 	}
+
+	if err := s.resolveSuffixKeys(); err != nil {
+		return nil, err
+	}
+	if err := s.resolveGroupBy(); err != nil {
+		return nil, err
+	}
+
+	sc, err := newScheme(s)
+	if err != nil {
+		return nil, err
+	}
+	s.scheme = sc
+
 	return s, nil
 }
 
 // Compare returns an integer comparing two version strings.
 // The result will be 0 if v1==v2, -1 if v1 < v2, and +1 if v1 > v2.
 func (s *sorter) Compare(v1, v2 string) (int, error) {
-	if s.prefix != nil {
-		loc1 := s.prefix.FindStringIndex(v1)
-		loc2 := s.prefix.FindStringIndex(v2)
-		if loc1 == nil || loc2 == nil {
-			return 0, fmt.Errorf("prefix is not match (v1: %q, v2: %q, prefix: %q)", v1, v2, s.prefix.String())
-		}
-		v1 = v1[loc1[1]:]
-		v2 = v2[loc2[1]:]
+	x, err := s.Parse(v1)
+	if err != nil {
+		return 0, err
+	}
+	y, err := s.Parse(v2)
+	if err != nil {
+		return 0, err
 	}
 
-	if s.suffix != nil {
-		loc1 := s.suffix.FindStringIndex(v1)
-		loc2 := s.suffix.FindStringIndex(v2)
-		if loc1 == nil || loc2 == nil {
-			return 0, fmt.Errorf("suffix is not match (v1: %q, v2: %q, suffix: %q)", v1, v2, s.suffix.String())
-		}
-		v1 = v1[0:loc1[0]]
-		v2 = v2[0:loc2[0]]
+	if err := s.checkPadding(x, y); err != nil {
+		return 0, err
 	}
-	nums1 := strings.SplitN(v1, ".", s.level)
-	nums2 := strings.SplitN(v2, ".", s.level)
 
-	for i := 0; i < len(nums1); i++ {
-		num1, err := strconv.Atoi(nums1[i])
+	return x.Compare(y), nil
+}
+
+// checkPadding returns an error if x and y have different segment counts when padding is PadError
+func (s *sorter) checkPadding(x, y Version) error {
+	if s.padding == PadError && len(x.Segments()) != len(y.Segments()) {
+		return fmt.Errorf("segment counts are different: %q and %q", x.original, y.original)
+	}
+
+	return nil
+}
+
+// Reject is a version rejected by Partition
+type Reject struct {
+	Version string
+	Err     error
+}
+
+// Sort sorts given versions.
+// Each version is parsed only once, and invalid versions are placed after valid ones in input order.
+// Use SortE or Partition to detect them.
+func (s *sorter) Sort(versions []string) {
+	parsed := make([]Version, 0, len(versions))
+	var invalid []string
+	for _, v := range versions {
+		x, err := s.Parse(v)
 		if err != nil {
-			return 0, err
+			invalid = append(invalid, v)
+			continue
 		}
-		num2, err := strconv.Atoi(nums2[i])
+		parsed = append(parsed, x)
+	}
+
+	s.sortVersions(parsed)
+	for i, x := range parsed {
+		versions[i] = x.original
+	}
+	copy(versions[len(parsed):], invalid)
+}
+
+// SortE sorts given versions like Sort.
+// It returns an error without modifying versions when an invalid version is contained.
+func (s *sorter) SortE(versions []string) error {
+	parsed := make([]Version, len(versions))
+	for i, v := range versions {
+		x, err := s.Parse(v)
 		if err != nil {
-			return 0, err
+			return err
 		}
+		if i > 0 {
+			if err := s.checkPadding(parsed[0], x); err != nil {
+				return err
+			}
+		}
+		parsed[i] = x
+	}
 
-		if num1 > num2 {
-			return 1, nil
-		} else if num1 < num2 {
-			return -1, nil
+	s.sortVersions(parsed)
+	for i, x := range parsed {
+		versions[i] = x.original
+	}
+
+	return nil
+}
+
+// Partition returns sorted valid versions and invalid ones with the reasons.
+// Rejects are in the same order as versions.
+func (s *sorter) Partition(versions []string) ([]string, []Reject) {
+	parsed := make([]Version, 0, len(versions))
+	var rejects []Reject
+	for _, v := range versions {
+		x, err := s.Parse(v)
+		if err == nil && len(parsed) > 0 {
+			err = s.checkPadding(parsed[0], x)
 		}
+		if err != nil {
+			rejects = append(rejects, Reject{Version: v, Err: err})
+			continue
+		}
+		parsed = append(parsed, x)
+	}
+
+	s.sortVersions(parsed)
+	valid := make([]string, len(parsed))
+	for i, x := range parsed {
+		valid[i] = x.original
 	}
 
-	return 0, nil
+	return valid, rejects
 }
 
-// Sort sorts given versions
-func (s *sorter) Sort(versions []string) {
-	sort.Slice(versions, func(i, j int) bool {
-		r, _ := s.Compare(versions[i], versions[j])
-		if s.order == Asc {
-			return r < 0
+// keyer is implemented by schemes which can encode values into keys ordered bytewise as Compare
+type keyer interface {
+	appendKey(b []byte, x interface{}) []byte
+}
+
+// sortKey is a precomputed key of versions[index]
+type sortKey struct {
+	key   string
+	index int
+}
+
+// sortVersions sorts parsed versions in the order of s.
+// If the scheme is keyer, compact keys are sorted instead of Version values
+// so that most comparisons are done by comparing bytes.
+func (s *sorter) sortVersions(versions []Version) {
+	k, ok := s.scheme.(keyer)
+	if !ok {
+		s.sortSlice(versions, func(i, j int) int {
+			return s.compareForSort(versions[i], versions[j])
+		})
+		return
+	}
+
+	// all keys share one buffer for memory locality
+	var buf []byte
+	ends := make([]int, len(versions))
+	for i, v := range versions {
+		buf = k.appendKey(buf, v.value)
+		ends[i] = len(buf)
+	}
+	all := string(buf)
+
+	keys := make([]sortKey, len(versions))
+	start := 0
+	for i, end := range ends {
+		keys[i] = sortKey{key: all[start:end], index: i}
+		start = end
+	}
+
+	s.sortSlice(keys, func(i, j int) int {
+		x, y := keys[i], keys[j]
+		if s.groupIndex > 0 {
+			// groups are always in ascending order
+			if r := strings.Compare(versions[x.index].group, versions[y.index].group); r != 0 {
+				return r
+			}
+		}
+
+		r := strings.Compare(x.key, y.key)
+		if r == 0 {
+			// suffix keys and tie-breakers are needed
+			return s.compareForSort(versions[x.index], versions[y.index])
+		}
+		if s.order == Desc {
+			return -r
 		}
-		return r > 0
+		return r
 	})
+
+	sorted := make([]Version, len(versions))
+	for i, key := range keys {
+		sorted[i] = versions[key.index]
+	}
+	copy(versions, sorted)
+}
+
+// Less reports whether x is placed before y by Sort.
+// It can be used to merge versions sorted by Sort.
+func (s *sorter) Less(x, y Version) bool {
+	return s.compareForSort(x, y) < 0
+}
+
+// sortSlice sorts slice by compare, which compares the i-th and j-th elements in the order of s
+func (s *sorter) sortSlice(slice interface{}, compare func(i, j int) int) {
+	less := func(i, j int) bool {
+		return compare(i, j) < 0
+	}
+
+	if s.stable || s.tieBreak == TieBreakInput {
+		sort.SliceStable(slice, less)
+	} else {
+		sort.Slice(slice, less)
+	}
+}
+
+// compareForSort compares x and y in the order of s.
+// Groups are compared first, and the tie of versions is broken.
+func (s *sorter) compareForSort(x, y Version) int {
+	// groups are always in ascending order
+	if r := strings.Compare(x.group, y.group); r != 0 {
+		return r
+	}
+
+	r := x.Compare(y)
+	if r == 0 {
+		switch s.tieBreak {
+		case TieBreakLexical:
+			r = strings.Compare(x.original, y.original)
+		case TieBreakSuffix:
+			if r = strings.Compare(x.suffix, y.suffix); r == 0 {
+				r = strings.Compare(x.prefix, y.prefix)
+			}
+		}
+	}
+
+	if s.order == Desc {
+		return -r
+	}
+	return r
+}
+
+// EmptyVersion returns Version which holds no version but is bound to s.
+// Text unmarshaled into it by UnmarshalText, UnmarshalJSON or Scan is parsed by s
+// (e.g. `cfg := config{Version: s.EmptyVersion()}` before `json.Unmarshal(data, &cfg)`).
+func (s *sorter) EmptyVersion() Version {
+	return Version{sorter: s}
 }
 
 // IsValid reports whether its argument v is a valid version string.
 func (s *sorter) IsValid(v string) bool {
-	// check prefix
+	_, err := s.Parse(v)
+	return err == nil
+}
+
+// ParseError is the error returned by Sorter.Parse and Sorter.Extract when a version is invalid
+type ParseError struct {
+	// Version is the text passed to Parse or Extract
+	Version string
+	// Err describes why Version is invalid without quoting Version (e.g. "segment 3 is not numeric").
+	// It is the error returned by Scheme.Parse if the scheme rejects the version.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %q", e.Err, e.Version)
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Parse parses v as a version string.
+// It returns *ParseError when prefix or suffix does not match or v is invalid in the scheme.
+func (s *sorter) Parse(v string) (Version, error) {
+	parsed, err := s.parse(v)
+	if err != nil {
+		switch err {
+		case errPrefixNotMatch:
+			err = fmt.Errorf("prefix is not match (prefix: %q)", s.prefix.String())
+		case errSuffixNotMatch:
+			err = fmt.Errorf("suffix is not match (suffix: %q)", s.suffix.String())
+		}
+		return Version{}, &ParseError{Version: v, Err: err}
+	}
+
+	return parsed, nil
+}
+
+var (
+	errPrefixNotMatch = errors.New("prefix is not match")
+	errSuffixNotMatch = errors.New("suffix is not match")
+)
+
+// parse parses v as Parse does, but returns errPrefixNotMatch, errSuffixNotMatch or an error of suffix key or scheme as is
+func (s *sorter) parse(v string) (Version, error) {
+	parsed := Version{sorter: s, original: v}
+	core := v
+
 	if s.prefix != nil {
-		loc := s.prefix.FindStringIndex(v)
+		loc := s.prefix.FindStringSubmatchIndex(core)
 		if loc == nil {
-			return false
+			return Version{}, errPrefixNotMatch
+		}
+		if s.groupIndex > 0 && loc[2*s.groupIndex] >= 0 {
+			parsed.group = core[loc[2*s.groupIndex]:loc[2*s.groupIndex+1]]
 		}
-		v = v[loc[1]:]
+		parsed.prefix = core[:loc[1]]
+		core = core[loc[1]:]
 	}
 
 	if s.suffix != nil {
-		loc := s.suffix.FindStringIndex(v)
+		loc := s.suffix.FindStringSubmatchIndex(core)
 		if loc == nil {
-			return false
+			return Version{}, errSuffixNotMatch
 		}
-		v = v[0:loc[0]]
-	}
-
-	// check level
-	nums := strings.Split(v, ".")
-	if s.level > 0 && len(nums) != s.level {
-		return false
+		keys, err := s.suffixKeyValues(core, loc)
+		if err != nil {
+			return Version{}, err
+		}
+		parsed.suffix = core[loc[0]:]
+		parsed.suffixKeys = keys
+		core = core[:loc[0]]
 	}
 
-	// check each level format
-	for _, n := range nums {
-		if _, err := strconv.Atoi(n); err != nil || n[0] == '+' || n[0] == '-' {
-			return false
-		}
+	x, err := s.scheme.Parse(core)
+	if err != nil {
+		return Version{}, err
 	}
+	parsed.core = core
+	parsed.value = x
 
-	return true
+	return parsed, nil
 }
@@ -16,6 +16,8 @@ package vsort
 
 import (
 	"fmt"
+	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -34,6 +36,12 @@ func TestSorterCompare(t *testing.T) {
 		{v1: "0.1.0", v2: "0.1.1", expected: -1},
 		{v1: "0.1.0", v2: "0.0.1", expected: 1},
 		{v1: "0.2.0", v2: "0.10.1", expected: -1},
+		{v1: "1.2", v2: "1.2.0", expected: 0},
+		{v1: "1.2.1", v2: "1.2", expected: 1},
+		{v1: "1.20231017123045123456", v2: "1.9223372036854775807", expected: 1},
+		{v1: "1.123456789012345678901234567890", v2: "1.123456789012345678901234567891", expected: -1},
+		{v1: "1.0000000000000000000000000000001", v2: "1.1", expected: 0},
+		{v1: "99999999999999999999999999999999.0", v2: "100000000000000000000000000000000", expected: -1},
 		{
 			options:  []Option{WithPrefix("v")},
 			v1:       "v0.1.1",
@@ -58,6 +66,30 @@ func TestSorterCompare(t *testing.T) {
 			v2:       "0.1",
 			expected: 1,
 		},
+		{
+			options:  []Option{WithPadding(PadZero)},
+			v1:       "1.2.0.0",
+			v2:       "1.2",
+			expected: 0,
+		},
+		{
+			options:  []Option{WithPadding(PadLess)},
+			v1:       "1.2",
+			v2:       "1.2.0",
+			expected: -1,
+		},
+		{
+			options:  []Option{WithPadding(PadLess)},
+			v1:       "1.3",
+			v2:       "1.2.0",
+			expected: 1,
+		},
+		{
+			options:  []Option{WithPadding(PadError)},
+			v1:       "1.3.0",
+			v2:       "1.2.0",
+			expected: 1,
+		},
 	}
 
 	genSubtestName := func(c Case) string {
@@ -86,6 +118,28 @@ func TestSorterCompare(t *testing.T) {
 	}
 }
 
+func TestSorterCompareError(t *testing.T) {
+	cases := []struct {
+		options []Option
+		v1      string
+		v2      string
+	}{
+		{v1: "1.a", v2: "1.2"},
+		{v1: "1.2", v2: "1.b"},
+		{options: []Option{WithPadding(PadError)}, v1: "1.2", v2: "1.2.0"},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q<=>%q(%s)", tt.v1, tt.v2, tt.options), func(t *testing.T) {
+			s, err := NewSorter(tt.options...)
+			if assert.NoError(t, err) {
+				_, err := s.Compare(tt.v1, tt.v2)
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
 func TestSorterSort(t *testing.T) {
 	type Case struct {
 		versions []string
@@ -113,6 +167,41 @@ func TestSorterSort(t *testing.T) {
 			options:  []Option{WithLevel(2)},
 			expected: []string{"0.1", "0.2", "2.0", "10.0"},
 		},
+		{
+			versions: []string{"0.2.0", "x", "0.1.0", "v0.0.1", "0.10.0"},
+			options:  []Option{WithOrder(Desc)},
+			expected: []string{"0.10.0", "0.2.0", "0.1.0", "x", "v0.0.1"},
+		},
+		{
+			versions: []string{"1.2.0", "1.10", "1.2", "1.1.5"},
+			options:  []Option{WithPadding(PadLess)},
+			expected: []string{"1.1.5", "1.2", "1.2.0", "1.10"},
+		},
+		{
+			versions: []string{"v1.0.0", "release-1.0.0", "0.9", "1.0", "rc-1.0.0"},
+			options:  []Option{WithPrefix("[a-z-]*"), WithStable(true)},
+			expected: []string{"0.9", "v1.0.0", "release-1.0.0", "1.0", "rc-1.0.0"},
+		},
+		{
+			versions: []string{"v1.0.0", "release-1.0.0", "0.9", "1.0", "rc-1.0.0"},
+			options:  []Option{WithPrefix("[a-z-]*"), WithTieBreak(TieBreakInput), WithOrder(Desc)},
+			expected: []string{"v1.0.0", "release-1.0.0", "1.0", "rc-1.0.0", "0.9"},
+		},
+		{
+			versions: []string{"v1.0.0", "release-1.0.0", "0.9", "1.0", "rc-1.0.0"},
+			options:  []Option{WithPrefix("[a-z-]*"), WithTieBreak(TieBreakLexical)},
+			expected: []string{"0.9", "1.0", "rc-1.0.0", "release-1.0.0", "v1.0.0"},
+		},
+		{
+			versions: []string{"1.0.0-2", "v1.0.0-10", "1.0.0-1", "0.9.0-3", "v1.0.0-1"},
+			options:  []Option{WithPrefix("v?"), WithSuffix(`-\d+`), WithTieBreak(TieBreakSuffix)},
+			expected: []string{"0.9.0-3", "1.0.0-1", "v1.0.0-1", "v1.0.0-10", "1.0.0-2"},
+		},
+		{
+			versions: []string{"1.0.0-2", "v1.0.0-10", "1.0.0-1", "0.9.0-3", "v1.0.0-1"},
+			options:  []Option{WithPrefix("v?"), WithSuffix(`-\d+`), WithTieBreak(TieBreakSuffix), WithOrder(Desc)},
+			expected: []string{"1.0.0-2", "v1.0.0-10", "v1.0.0-1", "1.0.0-1", "0.9.0-3"},
+		},
 	}
 
 	genSubtestName := func(c Case) string {
@@ -133,6 +222,133 @@ func TestSorterSort(t *testing.T) {
 	}
 }
 
+func TestSorterSortE(t *testing.T) {
+	cases := []struct {
+		versions []string
+		options  []Option
+		expected []string
+		success  bool
+	}{
+		{
+			versions: []string{"0.2.0", "0.0.1", "0.10.0", "0.0.2"},
+			expected: []string{"0.0.1", "0.0.2", "0.2.0", "0.10.0"},
+			success:  true,
+		},
+		{
+			versions: []string{"0.2.0", "0.0.1", "0.10.0", "0.0.2"},
+			options:  []Option{WithOrder(Desc)},
+			expected: []string{"0.10.0", "0.2.0", "0.0.2", "0.0.1"},
+			success:  true,
+		},
+		{
+			versions: []string{"0.2.0", "v0.0.1", "0.10.0"},
+			expected: []string{"0.2.0", "v0.0.1", "0.10.0"},
+			success:  false,
+		},
+		{
+			versions: []string{"0.2.0", "0.1", "0.10.0"},
+			options:  []Option{WithPadding(PadError)},
+			expected: []string{"0.2.0", "0.1", "0.10.0"},
+			success:  false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q(%s)", tt.versions, tt.options), func(t *testing.T) {
+			copied := make([]string, len(tt.versions))
+			copy(copied, tt.versions)
+
+			s, err := NewSorter(tt.options...)
+			if assert.NoError(t, err) {
+				err := s.SortE(copied)
+				if tt.success {
+					assert.NoError(t, err)
+				} else {
+					assert.Error(t, err)
+				}
+				assert.Equal(t, tt.expected, copied)
+			}
+		})
+	}
+}
+
+func TestSorterPartition(t *testing.T) {
+	cases := []struct {
+		versions []string
+		options  []Option
+		valid    []string
+		rejects  []string
+	}{
+		{
+			versions: []string{"0.2.0", "0.0.1", "0.10.0"},
+			valid:    []string{"0.0.1", "0.2.0", "0.10.0"},
+		},
+		{
+			versions: []string{"0.2.0", "v0.0.1", "0.10.0", "0.x", ""},
+			valid:    []string{"0.2.0", "0.10.0"},
+			rejects:  []string{"v0.0.1", "0.x", ""},
+		},
+		{
+			versions: []string{"0.2.0", "0.1", "0.10.0"},
+			options:  []Option{WithPadding(PadError), WithOrder(Desc)},
+			valid:    []string{"0.10.0", "0.2.0"},
+			rejects:  []string{"0.1"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q(%s)", tt.versions, tt.options), func(t *testing.T) {
+			s, err := NewSorter(tt.options...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			valid, rejects := s.Partition(tt.versions)
+			assert.Equal(t, tt.valid, valid)
+
+			var rejected []string
+			for _, r := range rejects {
+				assert.Error(t, r.Err, r.Version)
+				rejected = append(rejected, r.Version)
+			}
+			assert.Equal(t, tt.rejects, rejected)
+		})
+	}
+}
+
+func TestSorterLess(t *testing.T) {
+	cases := []struct {
+		options  []Option
+		v1       string
+		v2       string
+		expected bool
+	}{
+		{v1: "0.1.0", v2: "0.2.0", expected: true},
+		{v1: "0.2.0", v2: "0.1.0", expected: false},
+		{v1: "0.1.0", v2: "0.1.0", expected: false},
+		{options: []Option{WithOrder(Desc)}, v1: "0.2.0", v2: "0.1.0", expected: true},
+		{options: []Option{WithPrefix("[a-z]*"), WithTieBreak(TieBreakLexical)}, v1: "a1.0", v2: "b1.0", expected: true},
+		{options: []Option{WithPrefix("(?P<g>[a-z]*)"), WithGroupBy("g"), WithOrder(Desc)}, v1: "a1.0", v2: "b2.0", expected: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%q<%q(%s)", tt.v1, tt.v2, tt.options), func(t *testing.T) {
+			s, err := NewSorter(tt.options...)
+			if !assert.NoError(t, err) {
+				return
+			}
+			x, err := s.Parse(tt.v1)
+			if !assert.NoError(t, err) {
+				return
+			}
+			y, err := s.Parse(tt.v2)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, s.Less(x, y))
+			}
+		})
+	}
+}
+
 func TestSorterIsValid(t *testing.T) {
 	type versionCase struct {
 		version  string
@@ -252,3 +468,141 @@ func TestSorterIsValid(t *testing.T) {
 		}
 	}
 }
+
+func TestNewSorter(t *testing.T) {
+	cases := []struct {
+		options []Option
+		success bool
+	}{
+		{options: []Option{}, success: true},
+		{options: []Option{WithScheme(SemVer)}, success: true},
+		{options: []Option{WithScheme("unknown")}, success: false},
+		{options: []Option{WithScheme(SemVer), WithLevel(3)}, success: false},
+		{options: []Option{WithLevel(0)}, success: false},
+		{options: []Option{WithPadding(PadLess)}, success: true},
+		{options: []Option{WithPadding(-1)}, success: false},
+		{options: []Option{WithStable(true), WithTieBreak(TieBreakLexical)}, success: true},
+		{options: []Option{WithTieBreak(-1)}, success: false},
+		{options: []Option{WithScheme(SemVer), WithPadding(PadError)}, success: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%s", tt.options), func(t *testing.T) {
+			_, err := NewSorter(tt.options...)
+			if tt.success {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSorterSortConsistentWithCompare(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	digits := func() string {
+		return []string{"0", "00", "1", "01", "2", "10", "123456789012345678901234567890"}[r.Intn(7)]
+	}
+	numeric := func() string {
+		v := digits()
+		for n := r.Intn(4); n > 0; n-- {
+			v += "." + digits()
+		}
+		return v
+	}
+	semver := func() string {
+		v := fmt.Sprintf("%d.%d.%d", r.Intn(2), r.Intn(2), r.Intn(2))
+		if r.Intn(2) == 0 {
+			v += "-" + []string{"alpha", "alpha.1", "alpha.beta", "1", "rc.2", "rc.10", "rc-1"}[r.Intn(7)]
+		}
+		return v
+	}
+	calver := func() string {
+		return fmt.Sprintf("%d.%02d.%s", 2020+r.Intn(2), 1+r.Intn(12), digits())
+	}
+
+	cases := []struct {
+		options  []Option
+		generate func() string
+	}{
+		{generate: numeric},
+		{options: []Option{WithPadding(PadLess)}, generate: numeric},
+		{options: []Option{WithOrder(Desc)}, generate: numeric},
+		{options: []Option{WithScheme(SemVer)}, generate: semver},
+		{options: []Option{WithScheme(GoMod)}, generate: func() string { return "v" + semver() }},
+		{options: []Option{WithScheme(CalVer), WithCalVerFormat("YYYY.0M.MICRO")}, generate: calver},
+	}
+
+	for _, tt := range cases {
+		t.Run(fmt.Sprintf("%s", tt.options), func(t *testing.T) {
+			s, err := NewSorter(tt.options...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			versions := make([]string, 1000)
+			for i := range versions {
+				versions[i] = tt.generate()
+			}
+
+			s.Sort(versions)
+			for i := 1; i < len(versions); i++ {
+				r, err := s.Compare(versions[i-1], versions[i])
+				if assert.NoError(t, err) && s.(*sorter).order == Desc {
+					r = -r
+				}
+				if !assert.True(t, r <= 0, "%q should not be after %q", versions[i-1], versions[i]) {
+					return
+				}
+			}
+		})
+	}
+}
+
+// benchmarkVersions returns n random versions like "v12.3.456"
+func benchmarkVersions(n int) []string {
+	r := rand.New(rand.NewSource(1))
+	versions := make([]string, n)
+	for i := range versions {
+		versions[i] = fmt.Sprintf("v%d.%d.%d", r.Intn(20), r.Intn(50), r.Intn(1000))
+	}
+
+	return versions
+}
+
+func benchmarkSort(b *testing.B, sortFunc func(s Sorter, versions []string)) {
+	s, err := NewSorter(WithPrefix("v"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, n := range []int{1000, 1000000} {
+		versions := benchmarkVersions(n)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			copied := make([]string, n)
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				copy(copied, versions)
+				b.StartTimer()
+
+				sortFunc(s, copied)
+			}
+		})
+	}
+}
+
+func BenchmarkSort(b *testing.B) {
+	benchmarkSort(b, func(s Sorter, versions []string) {
+		s.Sort(versions)
+	})
+}
+
+// BenchmarkSortByCompare is the baseline which parses versions on each comparison
+func BenchmarkSortByCompare(b *testing.B) {
+	benchmarkSort(b, func(s Sorter, versions []string) {
+		sort.Slice(versions, func(i, j int) bool {
+			r, _ := s.Compare(versions[i], versions[j])
+			return r < 0
+		})
+	})
+}